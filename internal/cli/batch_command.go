@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mph-llm-experiments/anote/internal/config"
+	"github.com/mph-llm-experiments/anote/internal/idea"
+)
+
+// batchOutput is the --json shape of a batch run.
+type batchOutput struct {
+	Applied bool               `json:"applied"`
+	DryRun  bool               `json:"dry_run,omitempty"`
+	Error   string             `json:"error,omitempty"`
+	Results []idea.BatchResult `json:"results"`
+}
+
+func batchCommand(cfg *config.Config) *Command {
+	var dryRun bool
+	var file string
+
+	cmd := &Command{
+		Name:  "batch",
+		Usage: "anote batch [--file FILE] [--dry-run] < ops.json",
+		Description: `Apply several operations at once, all or nothing.
+
+Reads a JSON array, or one JSON object per line, from stdin (or --file).
+Each object has an "op" of new, update, tag, link, log or reject:
+  {"op":"new","title":"Mentoring guide","kind":"plan","tags":["work"],"ref":"guide"}
+  {"op":"update","id":"12","state":"active","purpose":"Career"}
+  {"op":"tag","id":"$guide","tag":"q3"}
+  {"op":"link","id":"$guide","target":"12"}
+  {"op":"log","id":"$guide","message":"Outlined sections"}
+  {"op":"reject","id":"7","reason":"Superseded"}
+
+A new op's "ref" lets later ops refer to the idea it creates as "$ref".
+Every op is validated before anything is written; if one fails nothing is
+applied, and a failed write rolls back the ones before it. Configured rules
+apply to updated ideas. Pre- hooks run while validating, --dry-run included,
+and post- hooks once the batch is written.`,
+		Flags: flag.NewFlagSet("batch", flag.ContinueOnError),
+	}
+
+	cmd.Flags.BoolVar(&dryRun, "dry-run", false, "Validate the operations without writing anything")
+	cmd.Flags.StringVar(&file, "file", "", "Read operations from FILE instead of stdin")
+
+	cmd.Run = func(c *Command, args []string) error {
+		var in io.Reader = os.Stdin
+		if file != "" {
+			f, err := os.Open(file)
+			if err != nil {
+				return fmt.Errorf("failed to open operations: %w", err)
+			}
+			defer f.Close()
+			in = f
+		}
+
+		ops, err := idea.ParseBatch(in)
+		if err != nil {
+			return err
+		}
+
+		hk, err := loadHooks(cfg)
+		if err != nil {
+			return err
+		}
+		results, runErr := idea.RunBatch(cfg.IdeasDirectory, ops, idea.BatchOptions{DryRun: dryRun, Rules: cfg.Rules, Hooks: hk})
+		if results == nil {
+			return runErr
+		}
+
+		if globalFlags.JSON {
+			out := batchOutput{Applied: runErr == nil && !dryRun, DryRun: dryRun, Results: results}
+			if runErr != nil {
+				out.Error = runErr.Error()
+			}
+			if err := printJSON(out); err != nil {
+				return err
+			}
+			return runErr
+		}
+
+		if runErr != nil || !globalFlags.Quiet {
+			for n, r := range results {
+				status, detail := "ok", r.Error
+				if !r.OK {
+					status = "FAIL"
+				} else if r.IndexID > 0 {
+					detail = fmt.Sprintf("#%d %s", r.IndexID, r.Title)
+				} else {
+					detail = r.Title
+				}
+				fmt.Printf("%-4d %-7s %-5s %s\n", n+1, r.Op, status, detail)
+			}
+		}
+		if runErr != nil {
+			return runErr
+		}
+		for n, r := range results {
+			if r.Warning != "" {
+				fmt.Fprintf(os.Stderr, "Warning: operation %d: %s\n", n+1, r.Warning)
+			}
+		}
+
+		if !globalFlags.Quiet {
+			if dryRun {
+				fmt.Printf("Validated %d operation(s); nothing was written (--dry-run)\n", len(results))
+			} else {
+				fmt.Printf("Applied %d operation(s)\n", len(results))
+			}
+		}
+		return nil
+	}
+
+	return cmd
+}
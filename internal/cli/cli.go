@@ -41,33 +41,52 @@ func Run(cfg *config.Config, args []string) error {
 Commands:
   new        Create a new idea
   list       List ideas
+  views      List saved views (list --view NAME)
+  search     Full-text search across ideas
   show       Show idea details
   update     Update idea state or maturity
+  edit       Open an idea file in your editor
   delete     Delete an idea file
   reject     Reject an idea (with reason)
   tag        Add or remove tags
   link       Link related ideas
+  batch      Apply several operations from stdin, all or nothing
+  serve      Serve ideas over a local HTTP/JSON API
+  rpc        Speak JSON-RPC on stdin/stdout for agent tools
+  watch      Print events as ideas are created, changed or deleted
+  purposes   List purposes with idea counts
+  audit      Report ideas that break kinds.json rules
   sync       Sync files with Cloudflare R2
 
 Global Options:
   --config PATH  Use specific config file
   --dir PATH     Override ideas directory
   --json         Output in JSON format
+  --jsonl        Output one compact JSON object per line, streamed where possible
   --no-color     Disable color output
-  --quiet, -q    Minimal output`,
+  --quiet, -q    Minimal output (-q is --query for list)`,
 	}
 
 	root.Subcommands = append(root.Subcommands,
 		ideaNewCommand(cfg),
 		ideaListCommand(cfg),
+		viewsCommand(cfg),
+		searchCommand(cfg),
 		ideaShowCommand(cfg),
 		ideaUpdateCommand(cfg),
+		ideaEditCommand(cfg),
 		ideaLogCommand(cfg),
 		ideaDeleteCommand(cfg),
 		ideaRejectCommand(cfg),
 		ideaTagCommand(cfg),
 		ideaLinkCommand(cfg),
 		ideaProjectCommand(cfg),
+		batchCommand(cfg),
+		serveCommand(cfg),
+		rpcCommand(cfg),
+		watchCommand(cfg),
+		purposesCommand(cfg),
+		auditCommand(cfg),
 		syncCommand(cfg),
 		ideaMigrateCommand(cfg),
 	)
@@ -79,11 +79,16 @@ type GlobalFlags struct {
 	Dir     string
 	NoColor bool
 	JSON    bool
+	JSONL   bool // one compact JSON object per line; implies JSON
 	Quiet   bool
 }
 
 var globalFlags GlobalFlags
 
+// queryShortFlag lists the commands where -q means --query rather than
+// --quiet. --quiet still works there.
+var queryShortFlag = map[string]bool{"list": true}
+
 // GetGlobalFlags returns the current global flags.
 func GetGlobalFlags() *GlobalFlags {
 	return &globalFlags
@@ -118,7 +123,15 @@ func ParseGlobalFlags(args []string) ([]string, error) {
 			globalFlags.JSON = true
 			i++
 			continue
+		case "--jsonl":
+			globalFlags.JSON = true
+			globalFlags.JSONL = true
+			i++
+			continue
 		case "--quiet", "-q":
+			if arg == "-q" && len(remaining) > 0 && queryShortFlag[remaining[0]] {
+				break
+			}
 			globalFlags.Quiet = true
 			i++
 			continue
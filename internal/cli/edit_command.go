@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/mph-llm-experiments/anote/internal/config"
+	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/idea"
+)
+
+func ideaEditCommand(cfg *config.Config) *Command {
+	cmd := &Command{
+		Name:  "edit",
+		Usage: "anote edit <id>",
+		Description: `Open an idea's file in an editor.
+
+Uses the editor from config.toml, or $EDITOR when that is unset, then vi.
+When the editor exits the frontmatter is parsed and validated; if it is
+broken you are offered to reopen the file. modified is bumped only if the
+file changed. Rules and state-change hooks run as for update; if the save
+fails the file is put back and the edit kept in a temporary file.`,
+	}
+
+	cmd.Run = func(c *Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("usage: anote edit <id>")
+		}
+
+		kinds, err := loadKinds(cfg)
+		if err != nil {
+			return err
+		}
+
+		mut, err := loadMutator(cfg, kinds)
+		if err != nil {
+			return err
+		}
+
+		i, err := lookupIdea(cfg.IdeasDirectory, args[0])
+		if err != nil {
+			return err
+		}
+		original, err := os.ReadFile(i.FilePath)
+		if err != nil {
+			return fmt.Errorf("failed to read idea: %w", err)
+		}
+
+		var edited *denote.Idea
+		for {
+			if err := runEditor(cfg, i.FilePath); err != nil {
+				return err
+			}
+			current, err := os.ReadFile(i.FilePath)
+			if err != nil {
+				return fmt.Errorf("failed to read idea: %w", err)
+			}
+			// An earlier attempt may have been undone in the editor
+			if bytes.Equal(current, original) {
+				edited = nil
+				break
+			}
+
+			parsed, err := denote.ParseIdeaFile(i.FilePath)
+			if err == nil {
+				err = validateEdited(kinds, parsed)
+			}
+			if err == nil {
+				edited = parsed
+				break
+			}
+			if !isatty.IsTerminal(os.Stdin.Fd()) {
+				return fmt.Errorf("invalid frontmatter in %s: %w", i.FilePath, err)
+			}
+			fmt.Fprintf(os.Stderr, "Invalid frontmatter: %v\n", err)
+			if !confirm("Reopen in editor?") {
+				return fmt.Errorf("%s left with invalid frontmatter", i.FilePath)
+			}
+		}
+
+		if edited == nil {
+			if globalFlags.JSON {
+				return printJSON(i)
+			}
+			if !globalFlags.Quiet {
+				fmt.Printf("No changes to idea #%d: %q\n", i.IndexID, i.Title)
+			}
+			return nil
+		}
+
+		// Save like update does, so rules and state-change hooks run. If that
+		// fails, put the file back and keep the edit aside.
+		change := &idea.Change{Idea: edited, Before: i}
+		if err := mut.Save(change); err != nil {
+			return restoreEdited(i.FilePath, original, err)
+		}
+		warnHook(change.Warning)
+
+		// Keep the copied purpose_name on attached ideas in step with the title
+		synced := 0
+		if edited.Kind == denote.KindPurpose && edited.Title != i.Title {
+			synced, err = idea.SyncPurposeName(cfg.IdeasDirectory, edited)
+			if err != nil {
+				return fmt.Errorf("renamed purpose but failed to update attached ideas: %w", err)
+			}
+		}
+
+		if globalFlags.JSON {
+			return printJSON(edited)
+		}
+		if !globalFlags.Quiet {
+			fmt.Printf("Updated idea #%d: %q\n", edited.IndexID, edited.Title)
+			printFired(change)
+			if synced > 0 {
+				fmt.Printf("Updated purpose name on %d attached idea(s)\n", synced)
+			}
+		}
+		return nil
+	}
+
+	return cmd
+}
+
+// restoreEdited puts the original contents back at path after the edit made
+// there could not be saved, copying the edit to a temporary file first.
+func restoreEdited(path string, original []byte, saveErr error) error {
+	edit, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to save idea: %w", saveErr)
+	}
+	f, err := os.CreateTemp("", "anote-edit-*.md")
+	if err != nil {
+		return fmt.Errorf("failed to save idea: %w (and failed to keep the edit: %v)", saveErr, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(edit); err != nil {
+		return fmt.Errorf("failed to save idea: %w (and failed to keep the edit: %v)", saveErr, err)
+	}
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		return fmt.Errorf("failed to save idea: %w (edit kept in %s, but failed to restore %s: %v)", saveErr, f.Name(), path, err)
+	}
+	return fmt.Errorf("failed to save idea: %w (file restored; edit kept in %s)", saveErr, f.Name())
+}
+
+// runEditor opens path in the editor from cfg.EditorCommand.
+func runEditor(cfg *config.Config, path string) error {
+	editor := cfg.EditorCommand()
+
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", strings.Join(editor, " "), err)
+	}
+	return nil
+}
+
+// validateEdited applies denote.ValidateIdea to a hand-edited idea. Kind
+// and state are checked against kinds.json instead, which may define kinds
+// and states the built-in rules do not know.
+func validateEdited(kinds *denote.KindsConfig, i *denote.Idea) error {
+	kind := effectiveKind(i)
+	if !kinds.KindExists(kind) {
+		return invalidKindError(kinds, kind)
+	}
+	if !kinds.IsCompliant(kind, i.State) {
+		return fmt.Errorf("invalid state %q for kind %s: use %s",
+			i.State, kind, strings.Join(kinds.ValidStatesFor(kind), ", "))
+	}
+
+	builtin := *i
+	builtin.Kind = ""
+	if !denote.IsValidState(builtin.State) {
+		builtin.State = ""
+	}
+	return denote.ValidateIdea(&builtin)
+}
+
+// confirm asks a yes/no question on the terminal, defaulting to yes. It
+// answers no when stdin is not a terminal.
+func confirm(question string) bool {
+	if !isatty.IsTerminal(os.Stdin.Fd()) {
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "%s [Y/n] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "" || answer == "y" || answer == "yes"
+}
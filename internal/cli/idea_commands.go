@@ -1,34 +1,35 @@
 package cli
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
-	"sort"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/mph-llm-experiments/acore"
 	"github.com/mph-llm-experiments/anote/internal/config"
 	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/hooks"
 	"github.com/mph-llm-experiments/anote/internal/idea"
 )
 
 func ideaNewCommand(cfg *config.Config) *Command {
 	cmd := &Command{
 		Name:        "new",
-		Usage:       "anote new [--tag TAG]... [--kind KIND] [--body BODY] <title>",
-		Description: "Create a new idea",
+		Usage:       "anote new [--tag TAG]... [--kind KIND] [--purpose PURPOSE] [--body BODY | --template NAME] <title | --from FILE>",
+		Description: "Create a new idea, or one per line or bullet of FILE (- for stdin)",
 	}
 
 	cmd.Run = func(c *Command, args []string) error {
 		// Manual flag parsing to allow: new "title" --tag X or new --tag X "title"
 		var tags []string
 		var titleParts []string
-		var kind, body string
+		var kind, body, purposeRef, templateName, from string
 		for idx := 0; idx < len(args); idx++ {
 			if args[idx] == "--tag" && idx+1 < len(args) {
 				tags = append(tags, strings.TrimSpace(args[idx+1]))
@@ -36,33 +37,76 @@ func ideaNewCommand(cfg *config.Config) *Command {
 			} else if args[idx] == "--kind" && idx+1 < len(args) {
 				kind = strings.TrimSpace(args[idx+1])
 				idx++
+			} else if args[idx] == "--purpose" && idx+1 < len(args) {
+				purposeRef = strings.TrimSpace(args[idx+1])
+				idx++
 			} else if args[idx] == "--body" && idx+1 < len(args) {
 				body = args[idx+1]
 				idx++
+			} else if args[idx] == "--template" && idx+1 < len(args) {
+				templateName = strings.TrimSpace(args[idx+1])
+				idx++
+			} else if args[idx] == "--from" && idx+1 < len(args) {
+				from = args[idx+1]
+				idx++
 			} else if !strings.HasPrefix(args[idx], "-") {
 				titleParts = append(titleParts, args[idx])
 			}
 		}
 
+		if from != "" {
+			if len(titleParts) > 0 || body != "" {
+				return fmt.Errorf("--from reads titles and bodies from the input: drop the title and --body")
+			}
+			return captureIdeas(cfg, from, idea.BatchOp{Kind: kind, Tags: tags, Purpose: purposeRef, Template: templateName})
+		}
+
 		if len(titleParts) == 0 {
 			return fmt.Errorf("title required: anote new \"My idea title\"")
 		}
 
-		if kind != "" && !denote.IsValidKind(kind) {
-			return fmt.Errorf("invalid kind %q: use aspiration, belief, plan, note, or fact", kind)
+		title := strings.Join(titleParts, " ")
+		if body != "" && templateName != "" {
+			return fmt.Errorf("--body and --template are mutually exclusive")
 		}
 
-		title := strings.Join(titleParts, " ")
+		var purpose *denote.Idea
+		if purposeRef != "" {
+			p, err := idea.FindPurpose(cfg.IdeasDirectory, purposeRef)
+			if err != nil {
+				return err
+			}
+			purpose = p
+		}
 
-		created, err := idea.CreateIdea(cfg.IdeasDirectory, title, tags, kind, body)
+		hk, err := loadHooks(cfg)
 		if err != nil {
 			return err
 		}
 
+		created, err := idea.Create(cfg.IdeasDirectory, idea.NewIdea{
+			Title:    title,
+			Tags:     tags,
+			Kind:     kind,
+			Body:     body,
+			Template: templateName,
+			Purpose:  purpose,
+			Hooks:    hk,
+		})
+		if err != nil {
+			return err
+		}
+		warnHook(hk.Run(hooks.PostCreate, hooks.Payload{Idea: created, NewState: created.State}))
+
+		if created.PurposeID == "" && !globalFlags.Quiet {
+			if kinds, err := loadKinds(cfg); err == nil && kinds.PurposeRequired(created.Kind) {
+				fmt.Fprintf(os.Stderr, "Warning: %s ideas require a purpose; attach one with 'anote update %d --purpose <purpose>'\n",
+					created.Kind, created.IndexID)
+			}
+		}
+
 		if globalFlags.JSON {
-			data, _ := json.MarshalIndent(created, "", "  ")
-			fmt.Println(string(data))
-			return nil
+			return printJSON(created)
 		}
 
 		if !globalFlags.Quiet {
@@ -75,13 +119,141 @@ func ideaNewCommand(cfg *config.Config) *Command {
 	return cmd
 }
 
-// isTerminalState returns true for end-of-lifecycle states.
-func isTerminalState(state string) bool {
-	switch state {
-	case denote.StateImplemented, denote.StateArchived, denote.StateRejected, denote.StateDropped:
-		return true
+// captureIdeas creates an idea for each item read from path ("-" for stdin),
+// all or none. defaults supplies the kind, purpose and template for items
+// that do not set their own, and tags added to every item.
+func captureIdeas(cfg *config.Config, path string, defaults idea.BatchOp) error {
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open input: %w", err)
+		}
+		defer f.Close()
+		in = f
 	}
-	return false
+
+	kinds, err := loadKinds(cfg)
+	if err != nil {
+		return err
+	}
+	items, err := idea.ParseCapture(in, kinds)
+	if err != nil {
+		return err
+	}
+
+	ops := make([]idea.BatchOp, len(items))
+	for n, item := range items {
+		op := item.Op
+		if op.Kind == "" {
+			op.Kind = defaults.Kind
+		}
+		if op.Purpose == "" {
+			op.Purpose = defaults.Purpose
+		}
+		op.Template = defaults.Template
+		for _, t := range defaults.Tags {
+			if !containsStr(op.Tags, t) {
+				op.Tags = append(op.Tags, t)
+			}
+		}
+		ops[n] = op
+	}
+
+	hk, err := loadHooks(cfg)
+	if err != nil {
+		return err
+	}
+	results, err := idea.RunBatch(cfg.IdeasDirectory, ops, idea.BatchOptions{Hooks: hk})
+	if err != nil {
+		for n, r := range results {
+			if !r.OK {
+				fmt.Fprintf(os.Stderr, "line %d: %s\n", items[n].Line, r.Error)
+			}
+		}
+		return err
+	}
+
+	created := make([]*denote.Idea, 0, len(results))
+	for n, r := range results {
+		if r.Warning != "" {
+			fmt.Fprintf(os.Stderr, "Warning: line %d: %s\n", items[n].Line, r.Warning)
+		}
+		i, err := lookupIdea(cfg.IdeasDirectory, r.ID)
+		if err != nil {
+			return fmt.Errorf("created idea #%d but failed to read it back: %w", r.IndexID, err)
+		}
+		created = append(created, i)
+		if i.PurposeID == "" && kinds.PurposeRequired(i.Kind) && !globalFlags.Quiet {
+			fmt.Fprintf(os.Stderr, "Warning: %s ideas require a purpose; attach one with 'anote update %d --purpose <purpose>'\n",
+				i.Kind, i.IndexID)
+		}
+	}
+
+	if globalFlags.JSON {
+		return printJSONList(created, nil)
+	}
+	if !globalFlags.Quiet {
+		for _, i := range created {
+			fmt.Printf("Created idea #%d: %q (%s)\n", i.IndexID, i.Title, i.FilePath)
+		}
+	}
+	return nil
+}
+
+// loadKinds loads kinds.json from the ideas directory.
+func loadKinds(cfg *config.Config) (*denote.KindsConfig, error) {
+	kinds, err := denote.LoadKindsConfig(cfg.IdeasDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kinds config: %w", err)
+	}
+	return kinds, nil
+}
+
+// loadHooks returns the runner for the lifecycle hooks in the config.
+func loadHooks(cfg *config.Config) (*hooks.Runner, error) {
+	hk, err := hooks.New(cfg.IdeasDirectory, cfg.Hooks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hooks config: %w", err)
+	}
+	return hk, nil
+}
+
+// loadMutator returns the mutator that saves edited ideas, applying the
+// configured rules and state-change hooks.
+func loadMutator(cfg *config.Config, kinds *denote.KindsConfig) (*idea.Mutator, error) {
+	hk, err := loadHooks(cfg)
+	if err != nil {
+		return nil, err
+	}
+	mut, err := idea.NewMutator(cfg.IdeasDirectory, kinds, cfg.Rules, hk)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules config: %w", err)
+	}
+	return mut, nil
+}
+
+// printFired prints the log entries of the rules that fired on an edit.
+func printFired(change *idea.Change) {
+	if globalFlags.Quiet || globalFlags.JSON {
+		return
+	}
+	for _, entry := range change.Fired {
+		fmt.Println(entry)
+	}
+}
+
+// warnHook reports a failed post- hook. The change it followed stands, so
+// this is a warning rather than an error.
+func warnHook(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// invalidKindError reports an unknown kind along with the configured ones.
+func invalidKindError(kinds *denote.KindsConfig, kind string) error {
+	return fmt.Errorf("invalid kind %q: use %s", kind, strings.Join(kinds.AllKinds(), ", "))
 }
 
 func ideaListCommand(cfg *config.Config) *Command {
@@ -92,13 +264,24 @@ func ideaListCommand(cfg *config.Config) *Command {
 		tag        string
 		kindFilter string
 		plannedFor string
+		purposeRef string
+		queryStr   string
+		viewName   string
+		sortFlag   string
+		reverseArg bool
+		paging     pageFlags
+		templ      templateFlags
 	)
 
 	cmd := &Command{
-		Name:        "list",
-		Usage:       "anote list [--state STATE] [--maturity LEVEL] [--kind KIND] [--tag TAG] [--planned-for DATE] [-a]",
-		Description: "List ideas",
-		Flags:       flag.NewFlagSet("list", flag.ContinueOnError),
+		Name:  "list",
+		Usage: "anote list [--view NAME] [-q QUERY] [--state STATE] [--maturity LEVEL] [--kind KIND] [--tag TAG] [--purpose PURPOSE] [--planned-for DATE] [--sort KEY] [--reverse] [--fields F,...] [--limit N] [--offset N] [--format TEXT | --template FILE] [-a]",
+		Description: `List ideas, most recently modified first.
+
+With --jsonl and no --sort, --reverse or sorted view, ideas stream as their
+files are read: in directory order, not by modified, and --limit/--offset
+page over that order. Pass --sort modified to get the --json order.`,
+		Flags: flag.NewFlagSet("list", flag.ContinueOnError),
 	}
 
 	cmd.Flags.BoolVar(&all, "a", false, "Show all ideas including terminal states")
@@ -106,158 +289,260 @@ func ideaListCommand(cfg *config.Config) *Command {
 	cmd.Flags.StringVar(&state, "state", "", "Filter by state (accepts display labels like considering)")
 	cmd.Flags.StringVar(&maturity, "maturity", "", "Filter by maturity")
 	cmd.Flags.StringVar(&tag, "tag", "", "Filter by tag")
-	cmd.Flags.StringVar(&kindFilter, "kind", "", "Filter by kind (aspiration, belief, plan, note, or fact)")
+	cmd.Flags.StringVar(&kindFilter, "kind", "", "Filter by kind (as defined in kinds.json)")
 	cmd.Flags.StringVar(&plannedFor, "planned-for", "", "Filter by planned_for date (today, YYYY-MM-DD, or any)")
+	cmd.Flags.StringVar(&purposeRef, "purpose", "", "Filter by purpose (index_id, ULID, or title; none for unattached)")
+	cmd.Flags.StringVar(&queryStr, "q", "", "Filter by query, e.g. 'kind:belief -tag:old modified:>30d'")
+	cmd.Flags.StringVar(&queryStr, "query", "", "Filter by query (same as -q)")
+	cmd.Flags.StringVar(&viewName, "view", "", "Run a saved view (see anote views); other flags narrow it")
+	cmd.Flags.StringVar(&sortFlag, "sort", "", "Sort by "+strings.Join(idea.SortKeys, ", ")+" (default modified)")
+	cmd.Flags.BoolVar(&reverseArg, "reverse", false, "Reverse the sort order")
+	paging.register(cmd.Flags)
+	cmd.Flags.StringVar(&templ.format, "format", "", "Print each idea with a Go template, e.g. '{{.IndexID}}\\t{{.Title}}'")
+	cmd.Flags.StringVar(&templ.file, "template", "", "Render the whole list with a Go template file")
 
 	cmd.Run = func(c *Command, args []string) error {
-		scanner := denote.NewScanner(cfg.IdeasDirectory)
-		ideas, err := scanner.FindIdeas()
+		kinds, err := loadKinds(cfg)
 		if err != nil {
-			return fmt.Errorf("failed to scan ideas: %w", err)
+			return err
 		}
 
-		// Sort by modification time, most recent first
-		sort.Slice(ideas, func(i, j int) bool {
-			return ideas[i].ModTime.After(ideas[j].ModTime)
-		})
+		sortKey := "modified"
+		reverse := false
+		sortRequested := sortFlag != "" || reverseArg
+		var columns []string
+		if viewName != "" {
+			v, err := cfg.LookupView(viewName)
+			if err != nil {
+				return err
+			}
+			if queryStr, err = applyView(viewName, v, queryStr); err != nil {
+				return err
+			}
+			// Explicit flags win over the view's
+			if kindFilter == "" {
+				kindFilter = v.Kind
+			}
+			if state == "" {
+				state = v.State
+			}
+			if tag == "" {
+				tag = v.Tag
+			}
+			if purposeRef == "" {
+				purposeRef = v.Purpose
+			}
+			if v.Sort != "" {
+				sortKey = v.Sort
+				sortRequested = true
+			}
+			reverse = v.Reverse
+			columns = v.Columns
+		}
+		if sortFlag != "" {
+			if !idea.IsSortKey(sortFlag) {
+				return fmt.Errorf("invalid sort %q: use %s", sortFlag, strings.Join(idea.SortKeys, ", "))
+			}
+			sortKey = sortFlag
+			reverse = false
+		}
+		if reverseArg {
+			reverse = true
+		}
+		cols, err := resolveListColumns(columns)
+		if err != nil {
+			return err
+		}
+
+		if kindFilter != "" && !kinds.KindExists(kindFilter) {
+			return invalidKindError(kinds, kindFilter)
+		}
+
+		filterPurposeID := ""
+		if purposeRef != "" && strings.ToLower(purposeRef) != "none" {
+			p, err := idea.FindPurpose(cfg.IdeasDirectory, purposeRef)
+			if err != nil {
+				return err
+			}
+			filterPurposeID = p.ID
+		}
+
+		scanner := denote.NewScanner(cfg.IdeasDirectory)
+
+		var filter *idea.Filter
+		if queryStr != "" {
+			entries, err := scanner.Entries()
+			if err != nil {
+				return fmt.Errorf("failed to scan ideas: %w", err)
+			}
+			filter, err = idea.CompileFilter(queryStr, kinds, entries, time.Now())
+			if err != nil {
+				return fmt.Errorf("invalid query: %w", err)
+			}
+		}
 
 		// Resolve display label to canonical state for filtering
 		filterState := state
 		filterStateKind := ""
 		if state != "" {
-			filterState, filterStateKind = denote.ResolveDisplayState(state)
-			if !denote.IsValidState(filterState) {
+			filterState, filterStateKind = kinds.ResolveDisplayState(state)
+			if !kinds.HasState(filterState) {
 				return fmt.Errorf("invalid state %q", state)
 			}
 		}
 
-		// Filter
-		var filtered []*denote.Idea
-		for _, i := range ideas {
+		keep := func(i *denote.Idea) bool {
 			effectiveKind := i.Kind
 			if effectiveKind == "" {
 				effectiveKind = denote.KindAspiration
 			}
 
-			// Default: exclude terminal states unless -a or specific --state
-			if !all && filterState == "" && isTerminalState(i.State) {
-				continue
+			// Default: exclude the kind's terminal states unless -a or a specific state
+			if !all && filterState == "" && (filter == nil || !filter.FiltersState()) && kinds.IsTerminal(effectiveKind, i.State) {
+				return false
+			}
+
+			if filter != nil && !filter.Match(i) {
+				return false
 			}
 
 			if filterState != "" && i.State != filterState {
-				continue
+				return false
 			}
 
 			// If user typed a kind-specific label, also filter by the implied kind
 			if filterStateKind != "" && effectiveKind != filterStateKind {
-				continue
+				return false
 			}
 
 			if kindFilter != "" && effectiveKind != kindFilter {
-				continue
+				return false
 			}
 
 			if maturity != "" && i.Maturity != maturity {
-				continue
+				return false
 			}
 
 			if tag != "" && !i.HasTag(tag) {
-				continue
+				return false
+			}
+
+			if purposeRef != "" && i.PurposeID != filterPurposeID {
+				return false
 			}
 
 			if plannedFor != "" {
 				switch strings.ToLower(plannedFor) {
 				case "any":
 					if i.PlannedFor == "" {
-						continue
+						return false
 					}
 				case "today":
 					if i.PlannedFor != time.Now().Format("2006-01-02") {
-						continue
+						return false
 					}
 				default:
 					if i.PlannedFor != plannedFor {
-						continue
+						return false
 					}
 				}
 			}
 
-			filtered = append(filtered, i)
+			return true
 		}
 
-		// JSON output — use kind-specific display labels
-		if globalFlags.JSON {
-			type jsonIdea struct {
-				denote.Idea
+		type jsonIdea struct {
+			denote.Idea
+		}
+		fields, err := paging.validate(jsonIdea{})
+		if err != nil {
+			return err
+		}
+		if fields != nil && !globalFlags.JSON {
+			return fmt.Errorf("--fields applies to --json output")
+		}
+		if err := templ.check(fields); err != nil {
+			return err
+		}
+		var tmpl *template.Template
+		if templ.set() {
+			if tmpl, err = templ.parse(cfg, kinds); err != nil {
+				return err
 			}
-			var output []jsonIdea
-			for _, i := range filtered {
-				ek := i.Kind
-				if ek == "" {
-					ek = denote.KindAspiration
-				}
-				ji := jsonIdea{Idea: *i}
-				ji.State = denote.DisplayState(i.State, ek)
-				ji.Kind = ek
-				output = append(output, ji)
+		}
+
+		// Use kind-specific display labels in JSON
+		toJSON := func(i *denote.Idea) jsonIdea {
+			ek := i.Kind
+			if ek == "" {
+				ek = denote.KindAspiration
 			}
-			data, err := json.MarshalIndent(output, "", "  ")
+			ji := jsonIdea{Idea: *i}
+			ji.State = kinds.DisplayState(i.State, ek)
+			ji.Kind = ek
+			return ji
+		}
+
+		// --jsonl without a requested order streams ideas as they are read
+		if globalFlags.JSONL && !sortRequested {
+			skipped, emitted := 0, 0
+			var emitErr error
+			err := scanner.EachIdea(func(i *denote.Idea) bool {
+				if !keep(i) {
+					return true
+				}
+				if skipped < paging.offset {
+					skipped++
+					return true
+				}
+				if emitErr = emitJSONLine(toJSON(i), fields); emitErr != nil {
+					return false
+				}
+				emitted++
+				return paging.limit == 0 || emitted < paging.limit
+			})
 			if err != nil {
-				return fmt.Errorf("failed to marshal JSON: %w", err)
+				return fmt.Errorf("failed to scan ideas: %w", err)
 			}
-			fmt.Println(string(data))
-			return nil
+			return emitErr
 		}
 
-		// Tabular output
-		if len(filtered) == 0 {
-			if !globalFlags.Quiet {
-				fmt.Println("No ideas found.")
-			}
-			return nil
+		ideas, err := scanner.FindIdeas()
+		if err != nil {
+			return fmt.Errorf("failed to scan ideas: %w", err)
 		}
+		idea.SortIdeas(ideas, sortKey, reverse)
 
-		// Header
-		fmt.Printf("%-5s %-4s %-14s %-8s %-38s %s\n", "#", "KIND", "STATE", "MATURITY", "TITLE", "TAGS")
-		fmt.Printf("%-5s %-4s %-14s %-8s %-38s %s\n", "---", "----", "-----", "--------", strings.Repeat("-", 38), "----")
-
-		for _, i := range filtered {
-			effectiveKind := i.Kind
-			if effectiveKind == "" {
-				effectiveKind = denote.KindAspiration
+		var filtered []*denote.Idea
+		for _, i := range ideas {
+			if keep(i) {
+				filtered = append(filtered, i)
 			}
-			displayState := denote.DisplayState(i.State, effectiveKind)
+		}
+		filtered = page(filtered, paging)
 
-			kindShort := "A"
-			switch effectiveKind {
-			case denote.KindBelief:
-				kindShort = "B"
-			case denote.KindPlan:
-				kindShort = "P"
-			case denote.KindNote:
-				kindShort = "N"
-			case denote.KindFact:
-				kindShort = "F"
-			}
+		if tmpl != nil {
+			return templ.executeEach(os.Stdout, tmpl, filtered)
+		}
 
-			mat := i.Maturity
-			if mat == "" {
-				mat = "-"
-			}
-			if denote.IsSimpleKind(effectiveKind) {
-				mat = "-"
+		if globalFlags.JSON {
+			var output []jsonIdea
+			for _, i := range filtered {
+				output = append(output, toJSON(i))
 			}
+			return printJSONList(output, fields)
+		}
 
-			tags := strings.Join(i.Tags, ", ")
-
-			title := i.Title
-			if len(title) > 38 {
-				title = title[:35] + "..."
+		// Tabular output
+		if len(filtered) == 0 {
+			if !globalFlags.Quiet {
+				fmt.Println("No ideas found.")
 			}
-
-			fmt.Printf("%-5d %-4s %-14s %-8s %-38s %s\n", i.IndexID, kindShort, displayState, mat, title, tags)
+			return nil
 		}
 
+		printIdeaTable(kinds, cols, filtered)
+
 		return nil
 	}
 
@@ -286,44 +571,122 @@ func lookupIdea(dir string, ref string) (*denote.Idea, error) {
 func ideaShowCommand(cfg *config.Config) *Command {
 	cmd := &Command{
 		Name:        "show",
-		Usage:       "anote show <id>",
+		Usage:       "anote show <id> [--no-content | --max-content N] [--fields F,...] [--format TEXT | --template FILE]",
 		Description: "Show idea details",
 	}
 
+	type jsonIdea struct {
+		denote.Idea
+		Content          string `json:"content,omitempty"`
+		ContentTruncated bool   `json:"content_truncated,omitempty"`
+	}
+
 	cmd.Run = func(c *Command, args []string) error {
-		if len(args) == 0 {
+		// Manual flag parsing to allow: show <id> --no-content or show --no-content <id>
+		var idRef, fieldList string
+		var templ templateFlags
+		noContent := false
+		maxContent := 0
+		for idx := 0; idx < len(args); idx++ {
+			switch args[idx] {
+			case "--no-content":
+				noContent = true
+			case "--max-content", "--fields", "--format", "--template":
+				if idx+1 >= len(args) {
+					return fmt.Errorf("%s requires a value", args[idx])
+				}
+				name, value := args[idx], args[idx+1]
+				idx++
+				switch name {
+				case "--max-content":
+					n, err := strconv.Atoi(value)
+					if err != nil || n < 0 {
+						return fmt.Errorf("--max-content: %q is not a character count", value)
+					}
+					maxContent = n
+				case "--fields":
+					fieldList = value
+				case "--format":
+					templ.format = value
+				case "--template":
+					templ.file = value
+				}
+			default:
+				if idRef == "" {
+					idRef = args[idx]
+				}
+			}
+		}
+		if idRef == "" {
 			return fmt.Errorf("idea ID required: anote show <id>")
 		}
 
-		i, err := lookupIdea(cfg.IdeasDirectory, args[0])
+		var fields []string
+		if fieldList != "" {
+			if !globalFlags.JSON {
+				return fmt.Errorf("--fields applies to --json output")
+			}
+			var err error
+			if fields, err = parseFields(fieldList, jsonFields(jsonIdea{})); err != nil {
+				return err
+			}
+		}
+
+		kinds, err := loadKinds(cfg)
 		if err != nil {
 			return err
 		}
 
+		if err := templ.check(fields); err != nil {
+			return err
+		}
+
+		i, err := lookupIdea(cfg.IdeasDirectory, idRef)
+		if err != nil {
+			return err
+		}
+
+		if templ.set() {
+			tmpl, err := templ.parse(cfg, kinds)
+			if err != nil {
+				return err
+			}
+			if err := tmpl.Execute(os.Stdout, i); err != nil {
+				return fmt.Errorf("template: %w", err)
+			}
+			if templ.file == "" {
+				fmt.Println()
+			}
+			return nil
+		}
+
+		content, truncated := "", false
+		if !noContent {
+			content, truncated = truncateContent(idea.ExtractContent(i.Content), maxContent)
+		}
+
 		effectiveKind := i.Kind
 		if effectiveKind == "" {
 			effectiveKind = denote.KindAspiration
 		}
-		displayState := denote.DisplayState(i.State, effectiveKind)
+		displayState := kinds.DisplayState(i.State, effectiveKind)
 
 		// JSON output
 		if globalFlags.JSON {
-			type jsonIdea struct {
-				denote.Idea
-				Content string `json:"content,omitempty"`
-			}
 			ji := jsonIdea{
-				Idea:    *i,
-				Content: extractIdeaContent(i.Content),
+				Idea:             *i,
+				Content:          content,
+				ContentTruncated: truncated,
 			}
 			ji.State = displayState
 			ji.Kind = effectiveKind
-			data, err := json.MarshalIndent(ji, "", "  ")
-			if err != nil {
-				return fmt.Errorf("failed to marshal JSON: %w", err)
+			var v any = ji
+			if fields != nil {
+				if v, err = projectJSON(ji, fields); err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
 			}
-			fmt.Println(string(data))
-			return nil
+			return printJSON(v)
 		}
 
 		// Formatted output
@@ -343,6 +706,11 @@ func ideaShowCommand(cfg *config.Config) *Command {
 		if i.Modified != "" {
 			fmt.Printf("Modified:   %s\n", i.Modified)
 		}
+		if i.PurposeName != "" {
+			fmt.Printf("Purpose:    %s (%s)\n", i.PurposeName, i.PurposeID)
+		} else if i.PurposeID != "" {
+			fmt.Printf("Purpose:    %s\n", i.PurposeID)
+		}
 		if i.PlannedFor != "" {
 			fmt.Printf("Planned:    %s\n", i.PlannedFor)
 		}
@@ -354,10 +722,10 @@ func ideaShowCommand(cfg *config.Config) *Command {
 		if len(i.RelatedIdeas) > 0 {
 			fmt.Printf("Related ideas:\n")
 			scanner := denote.NewScanner(cfg.IdeasDirectory)
-			allIdeas, _ := scanner.FindIdeas()
+			entries, _ := scanner.Entries()
 			idMap := make(map[string]string)
-			for _, a := range allIdeas {
-				idMap[a.ID] = a.Title
+			for _, e := range entries {
+				idMap[e.ID] = e.Title
 			}
 			for _, relID := range i.RelatedIdeas {
 				title, ok := idMap[relID]
@@ -388,10 +756,12 @@ func ideaShowCommand(cfg *config.Config) *Command {
 		fmt.Printf("File:       %s\n", i.FilePath)
 
 		// Show content (everything after frontmatter)
-		content := extractIdeaContent(i.Content)
 		if content != "" {
 			fmt.Printf("\n%s", content)
 		}
+		if truncated {
+			fmt.Printf("…\n[content truncated at %d characters]\n", maxContent)
+		}
 
 		return nil
 	}
@@ -402,16 +772,19 @@ func ideaShowCommand(cfg *config.Config) *Command {
 func ideaUpdateCommand(cfg *config.Config) *Command {
 	cmd := &Command{
 		Name:        "update",
-		Usage:       "anote update <id> [--title TITLE] [--state STATE] [--maturity LEVEL] [--kind KIND] [--plan-for DATE]",
-		Description: "Update idea title, state, maturity, or kind",
+		Usage:       "anote update <id> [--title TITLE] [--state STATE [--force]] [--maturity LEVEL] [--kind KIND] [--purpose PURPOSE|none] [--plan-for DATE]",
+		Description: "Update idea title, state, maturity, kind, or purpose",
 	}
 
 	cmd.Run = func(c *Command, args []string) error {
 		// Manual flag parsing to allow: update <id> --state X or update --state X <id>
-		var state, maturity, kind, title, body, idRef, planFor string
+		var state, maturity, kind, title, body, idRef, planFor, purposeRef string
 		var addPerson, removePerson, addTask, removeTask, addIdea, removeIdea string
+		force := false
 		for idx := 0; idx < len(args); idx++ {
 			switch args[idx] {
+			case "--force":
+				force = true
 			case "--title":
 				if idx+1 < len(args) {
 					title = args[idx+1]
@@ -442,6 +815,11 @@ func ideaUpdateCommand(cfg *config.Config) *Command {
 					planFor = args[idx+1]
 					idx++
 				}
+			case "--purpose":
+				if idx+1 < len(args) {
+					purposeRef = args[idx+1]
+					idx++
+				}
 			case "--add-person":
 				if idx+1 < len(args) {
 					addPerson = args[idx+1]
@@ -484,8 +862,18 @@ func ideaUpdateCommand(cfg *config.Config) *Command {
 		}
 
 		hasRelationUpdate := addPerson != "" || removePerson != "" || addTask != "" || removeTask != "" || addIdea != "" || removeIdea != ""
-		if state == "" && maturity == "" && kind == "" && title == "" && body == "" && planFor == "" && !hasRelationUpdate {
-			return fmt.Errorf("nothing to update: provide --title, --body, --state, --maturity, --kind, --plan-for, or relationship flags")
+		if state == "" && maturity == "" && kind == "" && title == "" && body == "" && planFor == "" && purposeRef == "" && !hasRelationUpdate {
+			return fmt.Errorf("nothing to update: provide --title, --body, --state, --maturity, --kind, --purpose, --plan-for, or relationship flags")
+		}
+
+		kinds, err := loadKinds(cfg)
+		if err != nil {
+			return err
+		}
+
+		mut, err := loadMutator(cfg, kinds)
+		if err != nil {
+			return err
 		}
 
 		i, err := lookupIdea(cfg.IdeasDirectory, idRef)
@@ -493,6 +881,11 @@ func ideaUpdateCommand(cfg *config.Config) *Command {
 			return err
 		}
 
+		if kind != "" && !kinds.KindExists(kind) {
+			return invalidKindError(kinds, kind)
+		}
+
+		renamed := title != "" && title != i.Title
 		if title != "" {
 			i.Title = title
 		}
@@ -508,32 +901,32 @@ func ideaUpdateCommand(cfg *config.Config) *Command {
 
 		// Resolve display label to canonical state
 		if state != "" {
-			resolved, _ := denote.ResolveDisplayState(state)
+			resolved, _ := kinds.ResolveDisplayState(state)
 			if resolved == denote.StateRejected {
 				return fmt.Errorf("use 'anote reject <id> \"reason\"' to reject an idea")
 			}
 			state = resolved
-			if !denote.IsValidState(state) {
-				return fmt.Errorf("invalid state %q", state)
+			if !kinds.IsCompliant(effectiveKind, state) {
+				return fmt.Errorf("invalid state %q for kind %s: use %s",
+					state, effectiveKind, strings.Join(kinds.ValidStatesFor(effectiveKind), ", "))
 			}
-			if denote.IsSimpleKind(effectiveKind) && state != denote.StateActive && state != denote.StateArchived {
-				return fmt.Errorf("note and fact kinds only support active and archived states")
+			if !force {
+				if err := kinds.ValidateTransition(effectiveKind, i.State, state); err != nil {
+					return fmt.Errorf("%w (use --force to override)", err)
+				}
 			}
 			i.State = state
 		}
 
-		// Validate and set kind
+		// Set kind; the state is checked against it once rules have run
 		if kind != "" {
-			if !denote.IsValidKind(kind) {
-				return fmt.Errorf("invalid kind %q: use aspiration, belief, plan, note, or fact", kind)
-			}
 			i.Kind = kind
 		}
 
 		// Validate and set maturity
 		if maturity != "" {
-			if denote.IsSimpleKind(effectiveKind) {
-				return fmt.Errorf("note and fact kinds do not use maturity")
+			if !kinds.UsesMaturity(effectiveKind) {
+				return fmt.Errorf("%s ideas do not use maturity", effectiveKind)
 			}
 			if !denote.IsValidMaturity(maturity) {
 				return fmt.Errorf("invalid maturity %q: use crawl, walk, or run", maturity)
@@ -554,44 +947,91 @@ func ideaUpdateCommand(cfg *config.Config) *Command {
 			}
 		}
 
+		// Resolve and set purpose
+		if purposeRef != "" {
+			if strings.ToLower(purposeRef) == "none" {
+				i.PurposeID = ""
+				i.PurposeName = ""
+			} else {
+				p, err := idea.FindPurpose(cfg.IdeasDirectory, purposeRef)
+				if err != nil {
+					return err
+				}
+				if p.ID == i.ID {
+					return fmt.Errorf("a purpose cannot be attached to itself")
+				}
+				if effectiveKind == denote.KindPurpose {
+					if err := idea.CheckPurposeParent(cfg.IdeasDirectory, i, p); err != nil {
+						return err
+					}
+				}
+				i.PurposeID = p.ID
+				i.PurposeName = p.Title
+			}
+		}
+
+		// Rules may move the state or attach a purpose, so validate after them
+		change := &idea.Change{Idea: i, Description: body}
+		if err := mut.ApplyRules(change); err != nil {
+			return err
+		}
+
+		// The current state must remain valid for a new kind
+		if kind != "" && !kinds.IsCompliant(kind, i.State) {
+			return fmt.Errorf("state %q is not valid for kind %s: also pass --state (%s)",
+				i.State, kind, strings.Join(kinds.ValidStatesFor(kind), ", "))
+		}
+
+		// Purpose-required kinds cannot advance past seed without a purpose
+		if (state != "" || kind != "" || purposeRef != "" || len(change.Fired) > 0) && i.PurposeID == "" && kinds.PurposeRequiredFor(effectiveKind, i.State) {
+			return fmt.Errorf("%s ideas require a purpose to be %s: pass --purpose",
+				effectiveKind, kinds.DisplayState(i.State, effectiveKind))
+		}
+
 		// Apply cross-app relationship updates
 		if addPerson != "" {
 			acore.AddRelation(&i.RelatedPeople, addPerson)
-			acore.SyncRelation(i.Type, i.ID, addPerson)
 		}
 		if removePerson != "" {
 			acore.RemoveRelation(&i.RelatedPeople, removePerson)
-			acore.UnsyncRelation(i.Type, i.ID, removePerson)
 		}
 		if addTask != "" {
 			acore.AddRelation(&i.RelatedTasks, addTask)
-			acore.SyncRelation(i.Type, i.ID, addTask)
 		}
 		if removeTask != "" {
 			acore.RemoveRelation(&i.RelatedTasks, removeTask)
-			acore.UnsyncRelation(i.Type, i.ID, removeTask)
 		}
 		if addIdea != "" {
 			acore.AddRelation(&i.RelatedIdeas, addIdea)
-			acore.SyncRelation(i.Type, i.ID, addIdea)
 		}
 		if removeIdea != "" {
 			acore.RemoveRelation(&i.RelatedIdeas, removeIdea)
-			acore.UnsyncRelation(i.Type, i.ID, removeIdea)
 		}
 
-		i.Modified = time.Now().Format(time.RFC3339)
+		// Replaces the description (content before ## Log), preserving the log
+		if err := mut.Save(change); err != nil {
+			return err
+		}
+		warnHook(change.Warning)
 
-		if body != "" {
-			// Replace description (content before ## Log section), preserve log
-			existingContent := extractIdeaContent(i.Content)
-			newContent := replaceDescription(existingContent, body)
-			if err := denote.WriteIdeaFile(i.FilePath, i, newContent); err != nil {
-				return fmt.Errorf("failed to update idea: %w", err)
+		// Touch the other apps' files only once a hook can no longer veto
+		for _, ref := range []string{addPerson, addTask, addIdea} {
+			if ref != "" {
+				acore.SyncRelation(i.Type, i.ID, ref)
 			}
-		} else {
-			if err := denote.UpdateIdeaFrontmatter(i.FilePath, i); err != nil {
-				return fmt.Errorf("failed to update idea: %w", err)
+		}
+		for _, ref := range []string{removePerson, removeTask, removeIdea} {
+			if ref != "" {
+				acore.UnsyncRelation(i.Type, i.ID, ref)
+			}
+		}
+
+		// Keep the copied purpose_name on attached ideas in step with the title
+		synced := 0
+		if renamed && i.Kind == denote.KindPurpose {
+			synced, err = idea.SyncPurposeName(cfg.IdeasDirectory, i)
+			if err != nil {
+				return fmt.Errorf("renamed purpose but failed to update attached ideas: %w", err)
 			}
 		}
 
@@ -600,9 +1040,7 @@ func ideaUpdateCommand(cfg *config.Config) *Command {
 			if err != nil {
 				reloaded = i
 			}
-			data, _ := json.MarshalIndent(reloaded, "", "  ")
-			fmt.Println(string(data))
-			return nil
+			return printJSON(reloaded)
 		}
 
 		if !globalFlags.Quiet {
@@ -611,12 +1049,23 @@ func ideaUpdateCommand(cfg *config.Config) *Command {
 				fmt.Printf(" [kind: %s]", kind)
 			}
 			if state != "" {
-				fmt.Printf(" [state: %s]", denote.DisplayState(state, effectiveKind))
+				fmt.Printf(" [state: %s]", kinds.DisplayState(state, effectiveKind))
 			}
 			if maturity != "" {
 				fmt.Printf(" [maturity: %s]", maturity)
 			}
+			if purposeRef != "" {
+				purposeName := i.PurposeName
+				if purposeName == "" {
+					purposeName = "none"
+				}
+				fmt.Printf(" [purpose: %s]", purposeName)
+			}
 			fmt.Println()
+			printFired(change)
+			if synced > 0 {
+				fmt.Printf("Updated purpose name on %d attached idea(s)\n", synced)
+			}
 
 			// Encourage project link when aspiration goes active
 			if state == denote.StateActive && len(i.RelatedTasks) == 0 && effectiveKind == denote.KindAspiration {
@@ -633,26 +1082,35 @@ func ideaUpdateCommand(cfg *config.Config) *Command {
 func ideaDeleteCommand(cfg *config.Config) *Command {
 	cmd := &Command{
 		Name:        "delete",
-		Usage:       "anote delete <id> [--confirm]",
+		Usage:       "anote delete <id> [--confirm] [--detach | --reassign PURPOSE]",
 		Description: "Delete an idea file",
 	}
 
 	cmd.Run = func(c *Command, args []string) error {
 		if len(args) == 0 {
-			return fmt.Errorf("usage: anote delete <id> [--confirm]")
+			return fmt.Errorf("usage: anote delete <id> [--confirm] [--detach | --reassign PURPOSE]")
 		}
 
-		confirm := false
-		idRef := ""
-		for _, arg := range args {
-			if arg == "--confirm" {
+		confirm, detach := false, false
+		idRef, reassignRef := "", ""
+		for idx := 0; idx < len(args); idx++ {
+			switch {
+			case args[idx] == "--confirm":
 				confirm = true
-			} else if idRef == "" {
-				idRef = arg
+			case args[idx] == "--detach":
+				detach = true
+			case args[idx] == "--reassign" && idx+1 < len(args):
+				reassignRef = args[idx+1]
+				idx++
+			case idRef == "":
+				idRef = args[idx]
 			}
 		}
 		if idRef == "" {
-			return fmt.Errorf("usage: anote delete <id> [--confirm]")
+			return fmt.Errorf("usage: anote delete <id> [--confirm] [--detach | --reassign PURPOSE]")
+		}
+		if detach && reassignRef != "" {
+			return fmt.Errorf("--detach and --reassign are mutually exclusive")
 		}
 
 		i, err := lookupIdea(cfg.IdeasDirectory, idRef)
@@ -660,10 +1118,48 @@ func ideaDeleteCommand(cfg *config.Config) *Command {
 			return err
 		}
 
+		// A purpose with attached ideas needs to know what happens to them
+		var attached []*denote.Idea
+		var reassignTo *denote.Idea
+		if i.Kind == denote.KindPurpose {
+			attached, err = idea.AttachedTo(cfg.IdeasDirectory, i.ID)
+			if err != nil {
+				return fmt.Errorf("failed to find attached ideas: %w", err)
+			}
+			if len(attached) > 0 && !detach && reassignRef == "" {
+				return fmt.Errorf("purpose '%s' has %d attached idea(s): pass --detach or --reassign PURPOSE", i.Title, len(attached))
+			}
+			if reassignRef != "" {
+				reassignTo, err = idea.FindPurpose(cfg.IdeasDirectory, reassignRef)
+				if err != nil {
+					return err
+				}
+				if err := idea.CheckReassign(cfg.IdeasDirectory, i.ID, reassignTo); err != nil {
+					return err
+				}
+			}
+		}
+
 		if !confirm {
 			return fmt.Errorf("use --confirm to delete idea '%s' (%s)", i.Title, i.FilePath)
 		}
 
+		hk, err := loadHooks(cfg)
+		if err != nil {
+			return err
+		}
+		if err := hk.Run(hooks.PreDelete, hooks.Payload{Idea: i, OldState: i.State}); err != nil {
+			return err
+		}
+
+		moved := 0
+		if len(attached) > 0 {
+			moved, err = idea.ReassignPurpose(cfg.IdeasDirectory, i.ID, reassignTo)
+			if err != nil {
+				return fmt.Errorf("failed to update attached ideas: %w", err)
+			}
+		}
+
 		if err := os.Remove(i.FilePath); err != nil {
 			return fmt.Errorf("failed to delete idea: %w", err)
 		}
@@ -675,13 +1171,24 @@ func ideaDeleteCommand(cfg *config.Config) *Command {
 				"title":    i.Title,
 				"file":     i.FilePath,
 			}
-			data, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(data))
-			return nil
+			if moved > 0 {
+				if reassignTo != nil {
+					result["reassigned"] = moved
+					result["reassigned_to"] = reassignTo.ID
+				} else {
+					result["detached"] = moved
+				}
+			}
+			return printJSON(result)
 		}
 
 		if !globalFlags.Quiet {
 			fmt.Printf("Deleted idea #%d: %s\n", i.IndexID, i.Title)
+			if moved > 0 && reassignTo != nil {
+				fmt.Printf("Reassigned %d idea(s) to purpose '%s'\n", moved, reassignTo.Title)
+			} else if moved > 0 {
+				fmt.Printf("Detached %d idea(s)\n", moved)
+			}
 		}
 		return nil
 	}
@@ -692,15 +1199,31 @@ func ideaDeleteCommand(cfg *config.Config) *Command {
 func ideaRejectCommand(cfg *config.Config) *Command {
 	cmd := &Command{
 		Name:        "reject",
-		Usage:       "anote reject <id> <reason>",
+		Usage:       "anote reject <id> <reason> [--force]",
 		Description: "Reject an idea (reason required)",
 	}
 
 	cmd.Run = func(c *Command, args []string) error {
+		force := false
+		var rest []string
+		for _, arg := range args {
+			if arg == "--force" {
+				force = true
+			} else {
+				rest = append(rest, arg)
+			}
+		}
+		args = rest
+
 		if len(args) < 2 {
 			return fmt.Errorf("usage: anote reject <id> \"reason for rejection\"")
 		}
 
+		kinds, err := loadKinds(cfg)
+		if err != nil {
+			return err
+		}
+
 		i, err := lookupIdea(cfg.IdeasDirectory, args[0])
 		if err != nil {
 			return err
@@ -710,8 +1233,15 @@ func ideaRejectCommand(cfg *config.Config) *Command {
 		if effectiveKind == "" {
 			effectiveKind = denote.KindAspiration
 		}
-		if denote.IsSimpleKind(effectiveKind) {
-			return fmt.Errorf("note and fact kinds cannot be rejected; use 'anote update %s --state archived' instead", args[0])
+		if !kinds.IsCompliant(effectiveKind, denote.StateRejected) {
+			return fmt.Errorf("%s ideas cannot be rejected; use 'anote update %s --state STATE' with one of: %s",
+				effectiveKind, args[0], strings.Join(kinds.ValidStatesFor(effectiveKind), ", "))
+		}
+
+		if !force {
+			if err := kinds.ValidateTransition(effectiveKind, i.State, denote.StateRejected); err != nil {
+				return fmt.Errorf("%w (use --force to override)", err)
+			}
 		}
 
 		reason := strings.Join(args[1:], " ")
@@ -719,16 +1249,23 @@ func ideaRejectCommand(cfg *config.Config) *Command {
 			return fmt.Errorf("rejection reason cannot be empty")
 		}
 
+		mut, err := loadMutator(cfg, kinds)
+		if err != nil {
+			return err
+		}
+
 		i.State = denote.StateRejected
 		i.RejectedReason = reason
-		i.Modified = time.Now().Format(time.RFC3339)
 
-		if err := denote.UpdateIdeaFrontmatter(i.FilePath, i); err != nil {
+		change := &idea.Change{Idea: i}
+		if err := mut.Save(change); err != nil {
 			return fmt.Errorf("failed to reject idea: %w", err)
 		}
+		warnHook(change.Warning)
 
 		if !globalFlags.Quiet {
 			fmt.Printf("Rejected idea #%d: %q — %s\n", i.IndexID, i.Title, reason)
+			printFired(change)
 		}
 
 		return nil
@@ -764,6 +1301,15 @@ func ideaTagCommand(cfg *config.Config) *Command {
 			return fmt.Errorf("usage: anote tag <id> <tag> [--remove]")
 		}
 
+		kinds, err := loadKinds(cfg)
+		if err != nil {
+			return err
+		}
+		mut, err := loadMutator(cfg, kinds)
+		if err != nil {
+			return err
+		}
+
 		i, err := lookupIdea(cfg.IdeasDirectory, idRef)
 		if err != nil {
 			return err
@@ -778,10 +1324,6 @@ func ideaTagCommand(cfg *config.Config) *Command {
 				}
 			}
 			i.Tags = newTags
-
-			if !globalFlags.Quiet {
-				fmt.Printf("Removed tag %q from idea #%d\n", tagName, i.IndexID)
-			}
 		} else {
 			// Add to frontmatter tags (skip duplicates)
 			found := false
@@ -794,15 +1336,21 @@ func ideaTagCommand(cfg *config.Config) *Command {
 			if !found {
 				i.Tags = append(i.Tags, tagName)
 			}
+		}
 
-			if !globalFlags.Quiet {
-				fmt.Printf("Added tag %q to idea #%d\n", tagName, i.IndexID)
-			}
+		change := &idea.Change{Idea: i}
+		if err := mut.Save(change); err != nil {
+			return err
 		}
+		warnHook(change.Warning)
 
-		i.Modified = time.Now().Format(time.RFC3339)
-		if err := denote.UpdateIdeaFrontmatter(i.FilePath, i); err != nil {
-			return fmt.Errorf("failed to update frontmatter: %w", err)
+		if !globalFlags.Quiet {
+			if remove {
+				fmt.Printf("Removed tag %q from idea #%d\n", tagName, i.IndexID)
+			} else {
+				fmt.Printf("Added tag %q to idea #%d\n", tagName, i.IndexID)
+			}
+			printFired(change)
 		}
 
 		return nil
@@ -833,12 +1381,10 @@ func ideaLinkCommand(cfg *config.Config) *Command {
 			return fmt.Errorf("second idea: %w", err)
 		}
 
-		now := time.Now().Format(time.RFC3339)
-
 		// Add idea2's ID to idea1's related ideas (skip duplicates)
 		if !containsStr(idea1.RelatedIdeas, idea2.ID) {
 			acore.AddRelation(&idea1.RelatedIdeas, idea2.ID)
-			idea1.Modified = now
+			idea.Touch(idea1)
 			if err := denote.UpdateIdeaFrontmatter(idea1.FilePath, idea1); err != nil {
 				return fmt.Errorf("failed to update idea #%d: %w", idea1.IndexID, err)
 			}
@@ -847,7 +1393,7 @@ func ideaLinkCommand(cfg *config.Config) *Command {
 		// Add idea1's ID to idea2's related ideas (skip duplicates)
 		if !containsStr(idea2.RelatedIdeas, idea1.ID) {
 			acore.AddRelation(&idea2.RelatedIdeas, idea1.ID)
-			idea2.Modified = now
+			idea.Touch(idea2)
 			if err := denote.UpdateIdeaFrontmatter(idea2.FilePath, idea2); err != nil {
 				return fmt.Errorf("failed to update idea #%d: %w", idea2.IndexID, err)
 			}
@@ -891,7 +1437,7 @@ func ideaProjectCommand(cfg *config.Config) *Command {
 		}
 
 		acore.AddRelation(&i.RelatedTasks, projectID)
-		i.Modified = time.Now().Format(time.RFC3339)
+		idea.Touch(i)
 
 		if err := denote.UpdateIdeaFrontmatter(i.FilePath, i); err != nil {
 			return fmt.Errorf("failed to update idea: %w", err)
@@ -931,18 +1477,24 @@ func ideaLogCommand(cfg *config.Config) *Command {
 		idRef := args[0]
 		message := strings.Join(args[1:], " ")
 
+		hk, err := loadHooks(cfg)
+		if err != nil {
+			return err
+		}
+
 		i, err := lookupIdea(cfg.IdeasDirectory, idRef)
 		if err != nil {
 			return err
 		}
 
-		existingContent := extractIdeaContent(i.Content)
-		newContent := addLogEntry(existingContent, message)
+		existingContent := idea.ExtractContent(i.Content)
+		newContent := idea.AddLogEntry(existingContent, message)
 
-		i.Modified = time.Now().Format(time.RFC3339)
+		idea.Touch(i)
 		if err := denote.WriteIdeaFile(i.FilePath, i, newContent); err != nil {
 			return fmt.Errorf("failed to write idea: %w", err)
 		}
+		warnHook(hk.Run(hooks.PostLog, hooks.Payload{Idea: i, OldState: i.State, NewState: i.State, Message: message}))
 
 		if !globalFlags.Quiet {
 			fmt.Printf("Logged to idea #%d: %s\n", i.IndexID, i.Title)
@@ -953,65 +1505,3 @@ func ideaLogCommand(cfg *config.Config) *Command {
 
 	return cmd
 }
-
-// replaceDescription replaces the description portion of content (before ## Log),
-// preserving the log section.
-func replaceDescription(content, newDesc string) string {
-	logIdx := strings.Index(content, "\n## Log\n")
-	if logIdx == -1 {
-		logIdx = strings.Index(content, "## Log\n")
-		if logIdx == 0 {
-			// Content starts with ## Log, prepend description
-			return newDesc + "\n\n" + content
-		}
-		// No log section, just replace everything
-		return newDesc + "\n"
-	}
-	return newDesc + "\n" + content[logIdx:]
-}
-
-// addLogEntry appends a timestamped entry to the ## Log section.
-func addLogEntry(content, message string) string {
-	now := time.Now().Format("2006-01-02")
-	entry := fmt.Sprintf("- **%s** %s", now, message)
-
-	logIdx := strings.Index(content, "\n## Log\n")
-	if logIdx != -1 {
-		// Insert after the ## Log header
-		insertAt := logIdx + len("\n## Log\n")
-		return content[:insertAt] + entry + "\n" + content[insertAt:]
-	}
-
-	// Check if content starts with ## Log
-	if strings.HasPrefix(content, "## Log\n") {
-		insertAt := len("## Log\n")
-		return content[:insertAt] + entry + "\n" + content[insertAt:]
-	}
-
-	// No log section yet, append one
-	trimmed := strings.TrimRight(content, "\n")
-	if trimmed == "" {
-		return "## Log\n" + entry + "\n"
-	}
-	return trimmed + "\n\n## Log\n" + entry + "\n"
-}
-
-// extractIdeaContent extracts the body content after YAML frontmatter.
-func extractIdeaContent(fullContent string) string {
-	if !strings.HasPrefix(fullContent, "---\n") {
-		return fullContent
-	}
-
-	lines := strings.Split(fullContent, "\n")
-	for idx, line := range lines {
-		if idx == 0 {
-			continue
-		}
-		if line == "---" {
-			rest := strings.Join(lines[idx+1:], "\n")
-			return strings.TrimPrefix(rest, "\n")
-		}
-	}
-
-	return ""
-}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/mph-llm-experiments/anote/internal/config"
+	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/idea"
+)
+
+// captureStdout returns what fn prints to stdout.
+func captureStdout(t *testing.T, fn func() error) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	runErr := fn()
+	os.Stdout = stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	if runErr != nil {
+		t.Fatalf("command: %v", runErr)
+	}
+	return string(out)
+}
+
+func TestRejectCommand_Aspiration(t *testing.T) {
+	cfg := &config.Config{IdeasDirectory: t.TempDir()}
+	i, err := idea.CreateIdea(cfg.IdeasDirectory, "Learn the cello", nil, denote.KindAspiration, "")
+	if err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+
+	captureStdout(t, func() error {
+		return ideaRejectCommand(cfg).Execute([]string{i.ID, "not worth it"})
+	})
+
+	got, err := denote.ParseIdeaFile(i.FilePath)
+	if err != nil {
+		t.Fatalf("ParseIdeaFile: %v", err)
+	}
+	if got.State != denote.StateRejected || got.RejectedReason != "not worth it" {
+		t.Errorf("after reject: state %q, reason %q", got.State, got.RejectedReason)
+	}
+}
+
+func TestListCommand_HidesTerminalAspirations(t *testing.T) {
+	cfg := &config.Config{IdeasDirectory: t.TempDir()}
+	for _, title := range []string{"Done with this", "Still going"} {
+		if _, err := idea.CreateIdea(cfg.IdeasDirectory, title, nil, denote.KindAspiration, ""); err != nil {
+			t.Fatalf("CreateIdea: %v", err)
+		}
+	}
+	archived, err := lookupIdea(cfg.IdeasDirectory, "1")
+	if err != nil {
+		t.Fatalf("lookupIdea: %v", err)
+	}
+	archived.State = denote.StateArchived
+	if err := denote.UpdateIdeaFrontmatter(archived.FilePath, archived); err != nil {
+		t.Fatalf("UpdateIdeaFrontmatter: %v", err)
+	}
+
+	globalFlags.JSON = true
+	defer func() { globalFlags.JSON = false }()
+
+	for _, tt := range []struct {
+		args []string
+		want []string
+	}{
+		{nil, []string{"Still going"}},
+		{[]string{"-a"}, []string{"Done with this", "Still going"}},
+	} {
+		out := captureStdout(t, func() error {
+			return ideaListCommand(cfg).Execute(tt.args)
+		})
+		var listed []struct {
+			Title string `json:"title"`
+		}
+		if err := json.Unmarshal([]byte(out), &listed); err != nil {
+			t.Fatalf("list %v: %v\n%s", tt.args, err, out)
+		}
+		titles := map[string]bool{}
+		for _, l := range listed {
+			titles[l.Title] = true
+		}
+		if len(titles) != len(tt.want) {
+			t.Errorf("list %v: got %v, want %v", tt.args, titles, tt.want)
+		}
+		for _, w := range tt.want {
+			if !titles[w] {
+				t.Errorf("list %v: missing %q", tt.args, w)
+			}
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package cli
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
 
@@ -74,9 +73,7 @@ func ideaMigrateCommand(cfg *config.Config) *Command {
 			}
 
 			if globalFlags.JSON {
-				data, _ := json.MarshalIndent(migMap, "", "  ")
-				fmt.Println(string(data))
-				return nil
+				return printJSON(migMap)
 			}
 
 			if !globalFlags.Quiet {
@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// pageFlags are the --fields, --limit and --offset options shared by
+// commands that print many ideas.
+type pageFlags struct {
+	fields string
+	limit  int
+	offset int
+}
+
+// register adds the flags to fs.
+func (p *pageFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&p.fields, "fields", "", "Comma-separated JSON fields to output, e.g. id,title,state,tags")
+	fs.IntVar(&p.limit, "limit", 0, "Output at most N results (0 for all)")
+	fs.IntVar(&p.offset, "offset", 0, "Skip the first N results")
+}
+
+// parseArgs pulls the flags out of args for commands whose other arguments
+// are free-form, returning the rest. Both --flag value and --flag=value work.
+func (p *pageFlags) parseArgs(args []string) ([]string, error) {
+	var rest []string
+	for idx := 0; idx < len(args); idx++ {
+		name, value, hasValue := strings.Cut(args[idx], "=")
+		switch name {
+		case "--fields", "--limit", "--offset":
+		default:
+			rest = append(rest, args[idx])
+			continue
+		}
+		if !hasValue {
+			if idx+1 >= len(args) {
+				return nil, fmt.Errorf("%s requires a value", name)
+			}
+			value = args[idx+1]
+			idx++
+		}
+		if name == "--fields" {
+			p.fields = value
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %q is not a number", name, value)
+		}
+		if name == "--limit" {
+			p.limit = n
+		} else {
+			p.offset = n
+		}
+	}
+	return rest, nil
+}
+
+// validate checks the limits and parses --fields against the keys of
+// sample, a value of the JSON output type.
+func (p *pageFlags) validate(sample any) ([]string, error) {
+	if p.limit < 0 || p.offset < 0 {
+		return nil, fmt.Errorf("--limit and --offset cannot be negative")
+	}
+	if p.fields == "" {
+		return nil, nil
+	}
+	return parseFields(p.fields, jsonFields(sample))
+}
+
+// page returns the items selected by --offset and --limit.
+func page[T any](items []T, p pageFlags) []T {
+	if p.offset >= len(items) {
+		return items[:0]
+	}
+	items = items[p.offset:]
+	if p.limit > 0 && p.limit < len(items) {
+		items = items[:p.limit]
+	}
+	return items
+}
+
+// jsonFields returns the JSON object keys of struct value v, including those
+// of embedded structs.
+func jsonFields(v any) []string {
+	var names []string
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		for n := 0; n < t.NumField(); n++ {
+			f := t.Field(n)
+			tag := f.Tag.Get("json")
+			name, _, _ := strings.Cut(tag, ",")
+			if name == "-" {
+				continue
+			}
+			if f.Anonymous && name == "" {
+				walk(f.Type)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			names = append(names, name)
+		}
+	}
+	walk(reflect.TypeOf(v))
+	return names
+}
+
+// parseFields splits a --fields list and checks each name against allowed.
+func parseFields(list string, allowed []string) ([]string, error) {
+	known := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		known[name] = true
+	}
+	var fields []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !known[name] {
+			return nil, fmt.Errorf("unknown field %q: use %s", name, strings.Join(allowed, ", "))
+		}
+		fields = append(fields, name)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("--fields needs at least one field")
+	}
+	return fields, nil
+}
+
+// projectJSON encodes v keeping only fields, in that order. Fields v omits
+// (empty omitempty values) come out as null.
+func projectJSON(v any, fields []string) (json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for n, name := range fields {
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		if value, ok := all[name]; ok {
+			buf.Write(value)
+		} else {
+			buf.WriteString("null")
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// printJSON prints v as indented JSON, or as one compact line under --jsonl.
+func printJSON(v any) error {
+	if globalFlags.JSONL {
+		return emitJSONLine(v, nil)
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// emitJSONLine prints v as one compact line of JSON, projected to fields
+// when any are given.
+func emitJSONLine(v any, fields []string) error {
+	var data []byte
+	var err error
+	if len(fields) > 0 {
+		data, err = projectJSON(v, fields)
+	} else {
+		data, err = json.Marshal(v)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	data = append(data, '\n')
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// printJSONList prints items as an indented JSON array, projected to fields
+// when any are given. A nil slice prints as null, as it does unprojected.
+// Under --jsonl each item is a line of its own and an empty list prints
+// nothing.
+func printJSONList[T any](items []T, fields []string) error {
+	if globalFlags.JSONL {
+		for _, item := range items {
+			if err := emitJSONLine(item, fields); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var out any = items
+	if len(fields) > 0 {
+		var projected []json.RawMessage
+		if items != nil {
+			projected = make([]json.RawMessage, 0, len(items))
+		}
+		for _, item := range items {
+			raw, err := projectJSON(item, fields)
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			projected = append(projected, raw)
+		}
+		out = projected
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// truncateContent cuts s to at most max runes, reporting whether it did.
+// max 0 means no limit.
+func truncateContent(s string, max int) (string, bool) {
+	if max <= 0 || utf8.RuneCountInString(s) <= max {
+		return s, false
+	}
+	n := 0
+	for idx := range s {
+		if n == max {
+			return s[:idx], true
+		}
+		n++
+	}
+	return s, false
+}
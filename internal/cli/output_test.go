@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPage(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		limit, offset int
+		want          string
+	}{
+		{0, 0, "[1 2 3 4 5]"},
+		{2, 0, "[1 2]"},
+		{0, 3, "[4 5]"},
+		{2, 1, "[2 3]"},
+		{10, 4, "[5]"},
+		{0, 5, "[]"},
+		{2, 9, "[]"},
+	}
+	for _, tt := range tests {
+		got := fmt.Sprint(page(items, pageFlags{limit: tt.limit, offset: tt.offset}))
+		if got != tt.want {
+			t.Errorf("page(limit=%d, offset=%d) = %s, want %s", tt.limit, tt.offset, got, tt.want)
+		}
+	}
+}
+
+func TestPageFlags_ParseArgs(t *testing.T) {
+	var p pageFlags
+	rest, err := p.parseArgs([]string{"tag:work", "--limit", "5", "--offset=2", "--fields", "id,title", "draft"})
+	if err != nil {
+		t.Fatalf("parseArgs: %v", err)
+	}
+	if fmt.Sprint(rest) != "[tag:work draft]" || p.limit != 5 || p.offset != 2 || p.fields != "id,title" {
+		t.Errorf("parseArgs: rest=%v flags=%+v", rest, p)
+	}
+
+	for _, args := range [][]string{{"--limit"}, {"--offset", "two"}} {
+		if _, err := (&pageFlags{}).parseArgs(args); err == nil {
+			t.Errorf("parseArgs(%v): expected error", args)
+		}
+	}
+}
+
+func TestProjectJSON(t *testing.T) {
+	v := struct {
+		ID    string   `json:"id"`
+		Title string   `json:"title"`
+		Tags  []string `json:"tags,omitempty"`
+	}{ID: "a1", Title: "Run a 10k"}
+
+	tests := []struct {
+		fields []string
+		want   string
+	}{
+		{[]string{"id"}, `{"id":"a1"}`},
+		{[]string{"title", "id"}, `{"title":"Run a 10k","id":"a1"}`},
+		{[]string{"id", "tags"}, `{"id":"a1","tags":null}`},
+	}
+	for _, tt := range tests {
+		got, err := projectJSON(v, tt.fields)
+		if err != nil {
+			t.Fatalf("projectJSON(%v): %v", tt.fields, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("projectJSON(%v) = %s, want %s", tt.fields, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateContent(t *testing.T) {
+	tests := []struct {
+		s         string
+		max       int
+		want      string
+		truncated bool
+	}{
+		{"hello", 0, "hello", false},
+		{"hello", 5, "hello", false},
+		{"hello", 3, "hel", true},
+		{"héllo wörld", 4, "héll", true},
+		{"日本語", 2, "日本", true},
+		{"", 3, "", false},
+	}
+	for _, tt := range tests {
+		got, truncated := truncateContent(tt.s, tt.max)
+		if got != tt.want || truncated != tt.truncated {
+			t.Errorf("truncateContent(%q, %d) = (%q, %v), want (%q, %v)", tt.s, tt.max, got, truncated, tt.want, tt.truncated)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mph-llm-experiments/anote/internal/config"
+	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/idea"
+)
+
+// purposeSummary is a purpose with counts of the ideas attached to it.
+type purposeSummary struct {
+	ID       string         `json:"id"`
+	IndexID  int            `json:"index_id"`
+	Title    string         `json:"title"`
+	State    string         `json:"state"`
+	ParentID string         `json:"parent_id,omitempty"`
+	Total    int            `json:"total"`
+	Counts   map[string]int `json:"counts"`
+}
+
+// summarizePurposes groups index entries by purpose and counts attached ideas
+// by canonical state. Sub-purposes are not counted as ideas of their parent.
+// Purposes are sorted by title.
+func summarizePurposes(entries []denote.IndexEntry) []*purposeSummary {
+	parents := denote.PurposeParents(entries)
+	byID := make(map[string]*purposeSummary)
+	var purposes []*purposeSummary
+	for _, e := range entries {
+		if e.Kind != denote.KindPurpose {
+			continue
+		}
+		p := &purposeSummary{ID: e.ID, IndexID: e.IndexID, Title: e.Title, State: e.State, ParentID: parents[e.ID], Counts: map[string]int{}}
+		byID[e.ID] = p
+		purposes = append(purposes, p)
+	}
+
+	for _, e := range entries {
+		if e.PurposeID == "" || e.Kind == denote.KindPurpose {
+			continue
+		}
+		if p, ok := byID[e.PurposeID]; ok {
+			p.Total++
+			p.Counts[e.State]++
+		}
+	}
+
+	sort.Slice(purposes, func(i, j int) bool {
+		return strings.ToLower(purposes[i].Title) < strings.ToLower(purposes[j].Title)
+	})
+	return purposes
+}
+
+// formatStateCounts renders counts in the order states are listed for kinds,
+// e.g. "active 3, seed 2".
+func formatStateCounts(kinds *denote.KindsConfig, counts map[string]int) string {
+	var order []string
+	seen := make(map[string]bool)
+	for _, kind := range kinds.AllKinds() {
+		for _, s := range kinds.ValidStatesFor(kind) {
+			if !seen[s] {
+				seen[s] = true
+				order = append(order, s)
+			}
+		}
+	}
+	var rest []string
+	for s := range counts {
+		if !seen[s] {
+			rest = append(rest, s)
+		}
+	}
+	sort.Strings(rest)
+	order = append(order, rest...)
+
+	var parts []string
+	for _, s := range order {
+		if n := counts[s]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", s, n))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// purposeTreeNode is a purpose with the ideas attached to it and to all of
+// its sub-purposes, bucketed as seed, active (in progress) or terminal.
+type purposeTreeNode struct {
+	ID       string             `json:"id"`
+	IndexID  int                `json:"index_id"`
+	Title    string             `json:"title"`
+	State    string             `json:"state"`
+	Active   int                `json:"active"`
+	Seed     int                `json:"seed"`
+	Terminal int                `json:"terminal"`
+	Children []*purposeTreeNode `json:"children,omitempty"`
+}
+
+// buildPurposeTree converts the purpose hierarchy into tree nodes with
+// rolled-up counts.
+func buildPurposeTree(kinds *denote.KindsConfig, entries []denote.IndexEntry) []*purposeTreeNode {
+	type bucket struct{ active, seed, terminal int }
+	direct := make(map[string]*bucket)
+	for _, e := range entries {
+		if e.PurposeID == "" || e.Kind == denote.KindPurpose {
+			continue
+		}
+		b := direct[e.PurposeID]
+		if b == nil {
+			b = &bucket{}
+			direct[e.PurposeID] = b
+		}
+		kind := e.Kind
+		if kind == "" {
+			kind = denote.KindAspiration
+		}
+		switch {
+		case e.State == denote.StateSeed:
+			b.seed++
+		case kinds.IsTerminal(kind, e.State):
+			b.terminal++
+		default:
+			b.active++
+		}
+	}
+
+	var convert func(ns []*denote.PurposeNode) []*purposeTreeNode
+	convert = func(ns []*denote.PurposeNode) []*purposeTreeNode {
+		out := make([]*purposeTreeNode, 0, len(ns))
+		for _, n := range ns {
+			t := &purposeTreeNode{ID: n.Entry.ID, IndexID: n.Entry.IndexID, Title: n.Entry.Title, State: n.Entry.State}
+			if b := direct[n.Entry.ID]; b != nil {
+				t.Active, t.Seed, t.Terminal = b.active, b.seed, b.terminal
+			}
+			t.Children = convert(n.Children)
+			for _, c := range t.Children {
+				t.Active += c.Active
+				t.Seed += c.Seed
+				t.Terminal += c.Terminal
+			}
+			out = append(out, t)
+		}
+		return out
+	}
+	return convert(denote.BuildPurposeTree(entries))
+}
+
+// printPurposeTree prints nodes with box-drawing branches under prefix.
+func printPurposeTree(nodes []*purposeTreeNode, prefix string, root bool) {
+	for i, n := range nodes {
+		last := i == len(nodes)-1
+		branch, childPrefix := "", ""
+		if !root {
+			branch, childPrefix = "├── ", "│   "
+			if last {
+				branch, childPrefix = "└── ", "    "
+			}
+		}
+		label := prefix + branch + n.Title
+		if w := utf8.RuneCountInString(label); w > 40 {
+			label = string([]rune(label)[:37]) + "..."
+		} else {
+			label += strings.Repeat(" ", 40-w)
+		}
+		fmt.Printf("%-5d %s %6d %6d %8d\n", n.IndexID, label, n.Active, n.Seed, n.Terminal)
+		printPurposeTree(n.Children, prefix+childPrefix, false)
+	}
+}
+
+func purposesCommand(cfg *config.Config) *Command {
+	var tree bool
+
+	cmd := &Command{
+		Name:        "purposes",
+		Usage:       "anote purposes [--tree]",
+		Description: "List purposes with counts of attached ideas by state",
+		Flags:       flag.NewFlagSet("purposes", flag.ContinueOnError),
+	}
+
+	cmd.Flags.BoolVar(&tree, "tree", false, "Show the purpose hierarchy with counts rolled up from sub-purposes")
+
+	cmd.Run = func(c *Command, args []string) error {
+		kinds, err := loadKinds(cfg)
+		if err != nil {
+			return err
+		}
+
+		entries, err := denote.NewScanner(cfg.IdeasDirectory).Entries()
+		if err != nil {
+			return fmt.Errorf("failed to scan ideas: %w", err)
+		}
+
+		if tree {
+			return printPurposesTree(kinds, entries)
+		}
+
+		purposes := summarizePurposes(entries)
+
+		if globalFlags.JSON {
+			if purposes == nil {
+				purposes = []*purposeSummary{}
+			}
+			return printJSONList(purposes, nil)
+		}
+
+		if len(purposes) == 0 {
+			if !globalFlags.Quiet {
+				fmt.Println("No purposes found.")
+			}
+			return nil
+		}
+
+		fmt.Printf("%-5s %-30s %-5s %s\n", "#", "PURPOSE", "IDEAS", "BY STATE")
+		fmt.Printf("%-5s %-30s %-5s %s\n", "---", strings.Repeat("-", 30), "-----", "--------")
+		for _, p := range purposes {
+			title := p.Title
+			if len(title) > 30 {
+				title = title[:27] + "..."
+			}
+			fmt.Printf("%-5d %-30s %-5d %s\n", p.IndexID, title, p.Total, formatStateCounts(kinds, p.Counts))
+		}
+
+		return nil
+	}
+
+	return cmd
+}
+
+func auditCommand(cfg *config.Config) *Command {
+	cmd := &Command{
+		Name:        "audit",
+		Usage:       "anote audit <check>",
+		Description: "Report ideas that do not comply with kinds.json",
+	}
+	cmd.Subcommands = []*Command{auditPurposesCommand(cfg)}
+	return cmd
+}
+
+func auditPurposesCommand(cfg *config.Config) *Command {
+	var fix bool
+
+	cmd := &Command{
+		Name:        "purposes",
+		Usage:       "anote audit purposes [--fix]",
+		Description: "List missing, dangling and stale purpose references",
+		Flags:       flag.NewFlagSet("audit purposes", flag.ContinueOnError),
+	}
+
+	cmd.Flags.BoolVar(&fix, "fix", false, "Refresh stale purpose names from their purposes' titles")
+
+	cmd.Run = func(c *Command, args []string) error {
+		kinds, err := loadKinds(cfg)
+		if err != nil {
+			return err
+		}
+
+		scanner := denote.NewScanner(cfg.IdeasDirectory)
+		issues, err := scanner.PurposeIssues(kinds)
+		if err != nil {
+			return fmt.Errorf("failed to scan ideas: %w", err)
+		}
+
+		if fix {
+			fixed, err := syncStalePurposeNames(cfg.IdeasDirectory, issues)
+			if err != nil {
+				return err
+			}
+			if fixed > 0 {
+				if !globalFlags.Quiet && !globalFlags.JSON {
+					fmt.Printf("Refreshed purpose name on %d idea(s)\n", fixed)
+				}
+				if issues, err = scanner.PurposeIssues(kinds); err != nil {
+					return fmt.Errorf("failed to scan ideas: %w", err)
+				}
+			}
+		}
+
+		for idx := range issues {
+			issues[idx].State = kinds.DisplayState(issues[idx].State, issues[idx].Kind)
+		}
+
+		if globalFlags.JSON {
+			if issues == nil {
+				issues = []denote.PurposeIssue{}
+			}
+			return printJSONList(issues, nil)
+		}
+
+		if len(issues) == 0 {
+			if !globalFlags.Quiet {
+				fmt.Println("All purpose references are in order.")
+			}
+			return nil
+		}
+
+		fmt.Printf("%-5s %-4s %-14s %-40s %s\n", "#", "KIND", "STATE", "TITLE", "PROBLEM")
+		fmt.Printf("%-5s %-4s %-14s %-40s %s\n", "---", "----", "-----", strings.Repeat("-", 40), "-------")
+		for _, is := range issues {
+			title := is.Title
+			if len(title) > 40 {
+				title = title[:37] + "..."
+			}
+			problem := is.Problem
+			switch is.Problem {
+			case denote.PurposeProblemDangling:
+				problem += " (" + is.PurposeID + ")"
+			case denote.PurposeProblemStale:
+				problem += fmt.Sprintf(" (%q)", is.PurposeName)
+			}
+			fmt.Printf("%-5d %-4s %-14s %-40s %s\n", is.IndexID, kinds.Abbrev(is.Kind), is.State, title, problem)
+		}
+		if !globalFlags.Quiet {
+			fmt.Printf("\n%d issue(s). Attach a purpose with 'anote update <id> --purpose <purpose>'; refresh stale names with 'anote audit purposes --fix'\n", len(issues))
+		}
+
+		return nil
+	}
+
+	return cmd
+}
+
+// syncStalePurposeNames refreshes purpose_name on every idea attached to a
+// purpose that has a stale issue, returning the number of ideas updated.
+func syncStalePurposeNames(dir string, issues []denote.PurposeIssue) (int, error) {
+	done := make(map[string]bool)
+	total := 0
+	for _, is := range issues {
+		if is.Problem != denote.PurposeProblemStale || done[is.PurposeID] {
+			continue
+		}
+		done[is.PurposeID] = true
+		p, err := idea.FindPurpose(dir, is.PurposeID)
+		if err != nil {
+			return total, err
+		}
+		n, err := idea.SyncPurposeName(dir, p)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// printPurposesTree renders `purposes --tree` as JSON or a table.
+func printPurposesTree(kinds *denote.KindsConfig, entries []denote.IndexEntry) error {
+	nodes := buildPurposeTree(kinds, entries)
+
+	if globalFlags.JSON {
+		return printJSONList(nodes, nil)
+	}
+
+	if len(nodes) == 0 {
+		if !globalFlags.Quiet {
+			fmt.Println("No purposes found.")
+		}
+		return nil
+	}
+
+	fmt.Printf("%-5s %-40s %6s %6s %8s\n", "#", "PURPOSE", "ACTIVE", "SEED", "TERMINAL")
+	fmt.Printf("%-5s %-40s %6s %6s %8s\n", "---", strings.Repeat("-", 40), "------", "----", "--------")
+	printPurposeTree(nodes, "", true)
+	return nil
+}
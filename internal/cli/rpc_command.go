@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/mph-llm-experiments/anote/internal/config"
+)
+
+func rpcCommand(cfg *config.Config) *Command {
+	cmd := &Command{
+		Name:  "rpc",
+		Usage: "anote rpc",
+		Description: `Speak line-delimited JSON-RPC 2.0 on stdin and stdout.
+
+Send one request per line; each request with an id gets one response line:
+  {"jsonrpc":"2.0","id":1,"method":"tools"}
+  {"jsonrpc":"2.0","id":2,"method":"update","params":{"id":"12","state":"active"}}
+
+The tools method returns every method with a JSON Schema for its params
+(kinds and states come from kinds.json) plus each kind's states. Errors carry
+a code and data.type: invalid_params, validation, not_found, conflict, ...`,
+	}
+
+	cmd.Run = func(c *Command, args []string) error {
+		srv, err := newServer(cfg)
+		if err != nil {
+			return err
+		}
+		return srv.ServeRPC(os.Stdin, os.Stdout)
+	}
+
+	return cmd
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mph-llm-experiments/anote/internal/config"
+	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/idea"
+)
+
+// searchResultJSON is the --json shape of a search hit.
+type searchResultJSON struct {
+	ID       string         `json:"id"`
+	IndexID  int            `json:"index_id"`
+	Title    string         `json:"title"`
+	Kind     string         `json:"kind"`
+	State    string         `json:"state"`
+	Tags     []string       `json:"tags"`
+	File     string         `json:"file"`
+	Score    int            `json:"score"`
+	Snippets []idea.Snippet `json:"snippets"`
+}
+
+var searchHighlight = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("3"))
+
+// highlightSnippet wraps each match in s with the highlight style.
+func highlightSnippet(s idea.Snippet) string {
+	if globalFlags.NoColor {
+		return s.Text
+	}
+	var sb strings.Builder
+	prev := 0
+	for _, m := range s.Matches {
+		sb.WriteString(s.Text[prev:m[0]])
+		sb.WriteString(searchHighlight.Render(s.Text[m[0]:m[1]]))
+		prev = m[1]
+	}
+	sb.WriteString(s.Text[prev:])
+	return sb.String()
+}
+
+func searchCommand(cfg *config.Config) *Command {
+	cmd := &Command{
+		Name:  "search",
+		Usage: "anote search <query> [--fields F,...] [--limit N] [--offset N]",
+		Description: `Search titles, tags and bodies (including log entries), most relevant first.
+
+Terms are ANDed; use OR, NOT or -term, "quoted phrases" and (parentheses):
+  anote search mentoring '"career ladder"' -hiring
+  anote search '(coaching OR mentoring) leadership'
+
+--fields, --limit and --offset trim and page the results. Results are ranked
+once the scan completes, so --jsonl prints them then rather than streaming.`,
+	}
+
+	cmd.Run = func(c *Command, args []string) error {
+		var paging pageFlags
+		args, err := paging.parseArgs(args)
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("query required: anote search <query>")
+		}
+		fields, err := paging.validate(searchResultJSON{})
+		if err != nil {
+			return err
+		}
+		if fields != nil && !globalFlags.JSON {
+			return fmt.Errorf("--fields applies to --json output")
+		}
+
+		kinds, err := loadKinds(cfg)
+		if err != nil {
+			return err
+		}
+
+		results, err := idea.Search(cfg.IdeasDirectory, strings.Join(args, " "))
+		if err != nil {
+			return fmt.Errorf("invalid query: %w", err)
+		}
+		results = page(results, paging)
+
+		if globalFlags.JSON {
+			out := make([]searchResultJSON, 0, len(results))
+			for _, r := range results {
+				kind := r.Idea.Kind
+				if kind == "" {
+					kind = denote.KindAspiration
+				}
+				snippets := r.Snippets
+				if snippets == nil {
+					snippets = []idea.Snippet{}
+				}
+				out = append(out, searchResultJSON{
+					ID:       r.Idea.ID,
+					IndexID:  r.Idea.IndexID,
+					Title:    r.Idea.Title,
+					Kind:     kind,
+					State:    kinds.DisplayState(r.Idea.State, kind),
+					Tags:     r.Idea.Tags,
+					File:     r.Idea.FilePath,
+					Score:    r.Score,
+					Snippets: snippets,
+				})
+			}
+			return printJSONList(out, fields)
+		}
+
+		if len(results) == 0 {
+			if !globalFlags.Quiet {
+				fmt.Println("No matching ideas.")
+			}
+			return nil
+		}
+
+		fmt.Printf("%-5s %-5s %-4s %-14s %s\n", "#", "SCORE", "KIND", "STATE", "TITLE")
+		fmt.Printf("%-5s %-5s %-4s %-14s %s\n", "---", "-----", "----", "-----", "-----")
+		for _, r := range results {
+			kind := r.Idea.Kind
+			if kind == "" {
+				kind = denote.KindAspiration
+			}
+			fmt.Printf("%-5d %-5d %-4s %-14s %s\n", r.Idea.IndexID, r.Score, kinds.Abbrev(kind),
+				kinds.DisplayState(r.Idea.State, kind), r.Idea.Title)
+			if globalFlags.Quiet {
+				continue
+			}
+			for _, s := range r.Snippets {
+				fmt.Printf("      %4d: %s\n", s.Line, highlightSnippet(s))
+			}
+		}
+
+		return nil
+	}
+
+	return cmd
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+
+	"github.com/mph-llm-experiments/anote/internal/config"
+	"github.com/mph-llm-experiments/anote/internal/server"
+)
+
+func serveCommand(cfg *config.Config) *Command {
+	var addr string
+
+	cmd := &Command{
+		Name:  "serve",
+		Usage: "anote serve [--addr 127.0.0.1:PORT]",
+		Description: `Serve ideas over a local HTTP/JSON API.
+
+  GET    /ideas?q=&all=&sort=&reverse=&limit=&offset=
+  GET    /search?q=QUERY
+  GET    /ideas/{id}
+  POST   /ideas                   {"title","kind","tags","body","purpose"}
+  PATCH  /ideas/{id}              {"title","body","state","kind","maturity","purpose","plan_for","force"}
+  POST   /ideas/{id}/log          {"message"}
+  POST   /ideas/{id}/tags         {"tag"}
+  DELETE /ideas/{id}/tags/{tag}
+  POST   /ideas/{id}/links        {"target"}
+  POST   /ideas/{id}/reject       {"reason","force"}
+
+Responses carry an ETag of the idea's modified timestamp; send it back as
+If-Match on writes to get 412 instead of overwriting someone else's change.
+Writes apply the configured rules and run hooks as the CLI commands do; a
+failed post- hook is reported in the response's "warning".
+
+Only requests addressed to localhost or a loopback IP are served, and writes
+must be sent as Content-Type application/json, so web pages in a browser
+cannot use the API.`,
+		Flags: flag.NewFlagSet("serve", flag.ContinueOnError),
+	}
+
+	cmd.Flags.StringVar(&addr, "addr", "127.0.0.1:7787", "Address to listen on")
+
+	cmd.Run = func(c *Command, args []string) error {
+		srv, err := newServer(cfg)
+		if err != nil {
+			return err
+		}
+		if !globalFlags.Quiet {
+			fmt.Printf("Serving %s on http://%s\n", cfg.IdeasDirectory, addr)
+		}
+		if err := http.ListenAndServe(addr, srv.Handler()); err != nil {
+			return fmt.Errorf("serve: %w", err)
+		}
+		return nil
+	}
+
+	return cmd
+}
+
+// newServer returns the server for the ideas in cfg, checking the hooks and
+// rules now rather than on the first write.
+func newServer(cfg *config.Config) (*server.Server, error) {
+	kinds, err := loadKinds(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := loadMutator(cfg, kinds); err != nil {
+		return nil, err
+	}
+	hk, err := loadHooks(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return server.New(cfg.IdeasDirectory, cfg.Rules, hk), nil
+}
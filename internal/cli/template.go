@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mph-llm-experiments/anote/internal/config"
+	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/idea"
+)
+
+// templateFlags are the --format and --template options of list and show.
+type templateFlags struct {
+	format string
+	file   string
+}
+
+// set reports whether either flag was given.
+func (t templateFlags) set() bool {
+	return t.format != "" || t.file != ""
+}
+
+// check rejects combinations that make no sense with templated output.
+func (t templateFlags) check(fields []string) error {
+	if !t.set() {
+		return nil
+	}
+	if t.format != "" && t.file != "" {
+		return fmt.Errorf("use either --format or --template, not both")
+	}
+	if globalFlags.JSON {
+		return fmt.Errorf("--format and --template cannot be combined with --json or --jsonl")
+	}
+	if fields != nil {
+		return fmt.Errorf("--fields applies to --json output")
+	}
+	return nil
+}
+
+// parse builds the template. --format text may use \t and \n escapes.
+func (t templateFlags) parse(cfg *config.Config, kinds *denote.KindsConfig) (*template.Template, error) {
+	tmpl := template.New("format").Funcs(templateFuncs(cfg, kinds))
+	if t.file != "" {
+		data, err := os.ReadFile(t.file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		tmpl, err = tmpl.Parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", t.file, err)
+		}
+		return tmpl, nil
+	}
+	tmpl, err := tmpl.Parse(unescapeText(t.format))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format: %w", err)
+	}
+	return tmpl, nil
+}
+
+// unescapeText turns \t and \n into tabs and newlines outside {{actions}},
+// where the template's own string literals already handle them.
+func unescapeText(format string) string {
+	r := strings.NewReplacer(`\t`, "\t", `\n`, "\n")
+	var sb strings.Builder
+	for {
+		open := strings.Index(format, "{{")
+		if open < 0 {
+			sb.WriteString(r.Replace(format))
+			return sb.String()
+		}
+		end := strings.Index(format[open:], "}}")
+		if end < 0 {
+			sb.WriteString(r.Replace(format[:open]))
+			sb.WriteString(format[open:])
+			return sb.String()
+		}
+		end += open + 2
+		sb.WriteString(r.Replace(format[:open]))
+		sb.WriteString(format[open:end])
+		format = format[end:]
+	}
+}
+
+// executeEach runs --format once per idea, ending each with a newline, or a
+// --template file once with the whole list as its data.
+func (t templateFlags) executeEach(w io.Writer, tmpl *template.Template, ideas []*denote.Idea) error {
+	if t.file != "" {
+		if ideas == nil {
+			ideas = []*denote.Idea{}
+		}
+		return tmpl.Execute(w, ideas)
+	}
+	for _, i := range ideas {
+		if err := tmpl.Execute(w, i); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// templateFuncs are the helpers available to --format and --template.
+//
+//	join .Tags ","            tags joined by a separator
+//	kind .                    the kind, aspiration when unset
+//	displayState .            the state's kind-specific label
+//	date "Jan 2" .Created     reformat a timestamp or YYYY-MM-DD date
+//	ago .Modified             age such as 3d or 2w
+//	title "01KJ..."           title of an idea by ULID (e.g. in .RelatedIdeas)
+//	body .                    the markdown body without frontmatter
+//	upper, lower, truncate N  string helpers
+func templateFuncs(cfg *config.Config, kinds *denote.KindsConfig) template.FuncMap {
+	var titles map[string]string
+	return template.FuncMap{
+		"join": func(elems []string, sep string) string { return strings.Join(elems, sep) },
+		"kind": func(i *denote.Idea) string { return effectiveKind(i) },
+		"displayState": func(i *denote.Idea) string {
+			return kinds.DisplayState(i.State, effectiveKind(i))
+		},
+		"date": func(layout, v string) string {
+			ts, ok := parseTimestamp(v)
+			if !ok {
+				return v
+			}
+			return ts.Format(layout)
+		},
+		"ago": func(v string) string {
+			ts, ok := parseTimestamp(v)
+			if !ok {
+				return ""
+			}
+			return formatAge(time.Since(ts))
+		},
+		"title": func(id string) string {
+			if titles == nil {
+				titles = map[string]string{}
+				entries, _ := denote.NewScanner(cfg.IdeasDirectory).Entries()
+				for _, e := range entries {
+					titles[e.ID] = e.Title
+				}
+			}
+			if t, ok := titles[id]; ok {
+				return t
+			}
+			return id
+		},
+		"body":  func(i *denote.Idea) string { return idea.ExtractContent(i.Content) },
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"truncate": func(n int, s string) string {
+			if r := []rune(s); len(r) > n {
+				if n > 3 {
+					return string(r[:n-3]) + "..."
+				}
+				return string(r[:n])
+			}
+			return s
+		},
+	}
+}
+
+// parseTimestamp reads an RFC 3339 timestamp or a YYYY-MM-DD date.
+func parseTimestamp(v string) (time.Time, bool) {
+	if ts, err := time.Parse(time.RFC3339, v); err == nil {
+		return ts, true
+	}
+	if ts, err := time.ParseInLocation("2006-01-02", v, time.Local); err == nil {
+		return ts, true
+	}
+	return time.Time{}, false
+}
+
+// formatAge renders d in the largest whole unit: 5m, 3h, 2d, 6w or 1y.
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 14*24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dw", int(d.Hours()/(24*7)))
+	}
+	return fmt.Sprintf("%dy", int(d.Hours()/(24*365)))
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnescapeText(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{`{{.IndexID}}\t{{.Title}}`, "{{.IndexID}}\t{{.Title}}"},
+		{`a\nb\tc`, "a\nb\tc"},
+		{`{{join .Tags "\t"}}`, `{{join .Tags "\t"}}`},
+		{`{{join .Tags "\n"}}\n`, `{{join .Tags "\n"}}` + "\n"},
+		{`\t{{printf "%s\t" .Title}}\t`, "\t" + `{{printf "%s\t" .Title}}` + "\t"},
+		{`unclosed {{ .Title \t`, `unclosed {{ .Title \t`},
+		{`plain`, "plain"},
+	}
+	for _, tt := range tests {
+		if got := unescapeText(tt.format); got != tt.want {
+			t.Errorf("unescapeText(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0m"},
+		{45 * time.Minute, "45m"},
+		{5 * time.Hour, "5h"},
+		{day, "1d"},
+		{13 * day, "13d"},
+		{14 * day, "2w"},
+		{364 * day, "52w"},
+		{365 * day, "1y"},
+		{3 * 365 * day, "3y"},
+	}
+	for _, tt := range tests {
+		if got := formatAge(tt.d); got != tt.want {
+			t.Errorf("formatAge(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mph-llm-experiments/anote/internal/config"
+	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/idea"
+)
+
+// listColumn is a column of the anote list table.
+type listColumn struct {
+	header string
+	rule   string
+	width  int // 0 for unpadded; only sensible as the last column
+	value  func(kinds *denote.KindsConfig, i *denote.Idea) string
+}
+
+// listColumns are the columns a view may choose, by name.
+var listColumns = map[string]listColumn{
+	"index": {"#", "---", 5, func(_ *denote.KindsConfig, i *denote.Idea) string {
+		return fmt.Sprintf("%d", i.IndexID)
+	}},
+	"kind": {"KIND", "----", 4, func(kinds *denote.KindsConfig, i *denote.Idea) string {
+		return kinds.Abbrev(effectiveKind(i))
+	}},
+	"state": {"STATE", "-----", 14, func(kinds *denote.KindsConfig, i *denote.Idea) string {
+		return kinds.DisplayState(i.State, effectiveKind(i))
+	}},
+	"maturity": {"MATURITY", "--------", 8, func(kinds *denote.KindsConfig, i *denote.Idea) string {
+		if i.Maturity == "" || !kinds.UsesMaturity(effectiveKind(i)) {
+			return "-"
+		}
+		return i.Maturity
+	}},
+	"title": {"TITLE", strings.Repeat("-", 38), 38, func(_ *denote.KindsConfig, i *denote.Idea) string {
+		return i.Title
+	}},
+	"tags": {"TAGS", "----", 0, func(_ *denote.KindsConfig, i *denote.Idea) string {
+		return strings.Join(i.Tags, ", ")
+	}},
+	"purpose": {"PURPOSE", "-------", 20, func(_ *denote.KindsConfig, i *denote.Idea) string {
+		if i.PurposeName == "" {
+			return "-"
+		}
+		return i.PurposeName
+	}},
+	"created": {"CREATED", "-------", 10, func(_ *denote.KindsConfig, i *denote.Idea) string {
+		return dateOrDash(i.Created)
+	}},
+	"modified": {"MODIFIED", "--------", 10, func(_ *denote.KindsConfig, i *denote.Idea) string {
+		return dateOrDash(i.Modified)
+	}},
+	"planned_for": {"PLANNED", "-------", 10, func(_ *denote.KindsConfig, i *denote.Idea) string {
+		return dateOrDash(i.PlannedFor)
+	}},
+}
+
+// listColumnOrder is the order columns are offered in help text.
+var listColumnOrder = []string{"index", "kind", "state", "maturity", "title", "tags", "purpose", "created", "modified", "planned_for"}
+
+// defaultListColumns are the columns of a plain anote list.
+var defaultListColumns = []string{"index", "kind", "state", "maturity", "title", "tags"}
+
+func effectiveKind(i *denote.Idea) string {
+	if i.Kind == "" {
+		return denote.KindAspiration
+	}
+	return i.Kind
+}
+
+// dateOrDash returns the YYYY-MM-DD part of a timestamp, or "-" if unset.
+func dateOrDash(v string) string {
+	if v == "" {
+		return "-"
+	}
+	if len(v) > 10 {
+		return v[:10]
+	}
+	return v
+}
+
+// resolveListColumns checks column names, defaulting to defaultListColumns.
+func resolveListColumns(names []string) ([]listColumn, error) {
+	if len(names) == 0 {
+		names = defaultListColumns
+	}
+	cols := make([]listColumn, 0, len(names))
+	for _, name := range names {
+		col, ok := listColumns[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q: use %s", name, strings.Join(listColumnOrder, ", "))
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+// printIdeaTable prints ideas as a table of cols. Padded values longer than
+// their column are truncated.
+func printIdeaTable(kinds *denote.KindsConfig, cols []listColumn, ideas []*denote.Idea) {
+	row := func(cell func(c listColumn) string) {
+		var sb strings.Builder
+		for n, c := range cols {
+			v := cell(c)
+			if n == len(cols)-1 || c.width == 0 {
+				sb.WriteString(v)
+				if n < len(cols)-1 {
+					sb.WriteString(" ")
+				}
+				continue
+			}
+			fmt.Fprintf(&sb, "%-*s ", c.width, v)
+		}
+		fmt.Println(sb.String())
+	}
+
+	row(func(c listColumn) string { return c.header })
+	row(func(c listColumn) string { return c.rule })
+	for _, i := range ideas {
+		row(func(c listColumn) string {
+			v := c.value(kinds, i)
+			if c.width > 3 && len(v) > c.width {
+				v = v[:c.width-3] + "..."
+			}
+			return v
+		})
+	}
+}
+
+// viewJSON is the --json shape of a saved view.
+type viewJSON struct {
+	Name string `json:"name"`
+	config.View
+}
+
+func viewsCommand(cfg *config.Config) *Command {
+	cmd := &Command{
+		Name:  "views",
+		Usage: "anote views",
+		Description: `List saved views. Run one with: anote list --view NAME
+
+Views are [views.NAME] tables in the config file:
+  [views.pipeline]
+  description = "Work beliefs still being weighed"
+  kind = "belief"
+  state = "considering"
+  tag = "work"
+  query = "-tag:old"
+  sort = "modified"
+  columns = ["index", "state", "title", "purpose"]
+
+Columns: ` + strings.Join(listColumnOrder, ", "),
+	}
+
+	cmd.Run = func(c *Command, args []string) error {
+		names := cfg.ViewNames()
+
+		if globalFlags.JSON {
+			out := make([]viewJSON, 0, len(names))
+			for _, name := range names {
+				out = append(out, viewJSON{Name: name, View: cfg.Views[name]})
+			}
+			return printJSONList(out, nil)
+		}
+
+		if len(names) == 0 {
+			if !globalFlags.Quiet {
+				fmt.Printf("No views configured. Add [views.NAME] tables to %s\n", config.ConfigPath())
+			}
+			return nil
+		}
+
+		for _, name := range names {
+			if globalFlags.Quiet {
+				fmt.Println(name)
+				continue
+			}
+			v := cfg.Views[name]
+			fmt.Printf("%-16s %s\n", name, describeView(v))
+		}
+		return nil
+	}
+
+	return cmd
+}
+
+// describeView summarizes a view as its description or the list flags it
+// stands for.
+func describeView(v config.View) string {
+	if v.Description != "" {
+		return v.Description
+	}
+	var parts []string
+	add := func(flag, value string) {
+		if value != "" {
+			parts = append(parts, fmt.Sprintf("--%s %s", flag, value))
+		}
+	}
+	add("kind", v.Kind)
+	add("state", v.State)
+	add("tag", v.Tag)
+	add("purpose", v.Purpose)
+	if v.Query != "" {
+		parts = append(parts, fmt.Sprintf("-q '%s'", v.Query))
+	}
+	add("sort", v.Sort)
+	if v.Reverse {
+		parts = append(parts, "--reverse")
+	}
+	if len(parts) == 0 {
+		return "(all ideas)"
+	}
+	return strings.Join(parts, " ")
+}
+
+// applyView validates view v's sort and returns the list query with the view's
+// query ANDed in.
+func applyView(name string, v config.View, queryStr string) (string, error) {
+	if v.Sort != "" && !idea.IsSortKey(v.Sort) {
+		return "", fmt.Errorf("view %s: invalid sort %q: use %s", name, v.Sort, strings.Join(idea.SortKeys, ", "))
+	}
+	if _, err := resolveListColumns(v.Columns); err != nil {
+		return "", fmt.Errorf("view %s: %w", name, err)
+	}
+	switch {
+	case v.Query == "":
+		return queryStr, nil
+	case queryStr == "":
+		return v.Query, nil
+	}
+	return "(" + v.Query + ") (" + queryStr + ")", nil
+}
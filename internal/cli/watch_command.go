@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/mph-llm-experiments/anote/internal/config"
+	"github.com/mph-llm-experiments/anote/internal/denote"
+)
+
+func watchCommand(cfg *config.Config) *Command {
+	var interval time.Duration
+
+	cmd := &Command{
+		Name:  "watch",
+		Usage: "anote watch [--json] [--interval 1s]",
+		Description: `Print created, modified, deleted and state_changed events as idea files change.
+
+With --json each event is one line: {"type","time","file","changed","before","after"},
+where before and after are the idea's metadata on either side of the change.`,
+		Flags: flag.NewFlagSet("watch", flag.ContinueOnError),
+	}
+
+	cmd.Flags.DurationVar(&interval, "interval", time.Second, "How often to check for changes")
+
+	cmd.Run = func(c *Command, args []string) error {
+		if interval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+		kinds, err := loadKinds(cfg)
+		if err != nil {
+			return err
+		}
+
+		w, err := denote.NewWatcher(cfg.IdeasDirectory)
+		if err != nil {
+			return fmt.Errorf("failed to scan ideas: %w", err)
+		}
+		if !globalFlags.Quiet && !globalFlags.JSON {
+			fmt.Fprintf(os.Stderr, "Watching %s (Ctrl-C to stop)\n", cfg.IdeasDirectory)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+
+			events, err := w.Poll()
+			if err != nil {
+				return fmt.Errorf("failed to scan ideas: %w", err)
+			}
+			for _, e := range events {
+				if globalFlags.JSON {
+					if err := emitJSONLine(e, nil); err != nil {
+						return err
+					}
+					continue
+				}
+				fmt.Println(describeEvent(kinds, e))
+			}
+		}
+	}
+
+	return cmd
+}
+
+// describeEvent renders a watch event as one line of text.
+func describeEvent(kinds *denote.KindsConfig, e denote.WatchEvent) string {
+	i := e.After
+	if i == nil {
+		i = e.Before
+	}
+	line := fmt.Sprintf("%s %-13s #%d %s", time.Now().Format("15:04:05"), e.Type, i.IndexID, i.Title)
+	switch e.Type {
+	case denote.EventStateChanged:
+		line += fmt.Sprintf(": %s → %s", kinds.DisplayState(e.Before.State, effectiveKind(e.Before)),
+			kinds.DisplayState(e.After.State, effectiveKind(e.After)))
+	case denote.EventModified:
+		line += " (" + strings.Join(e.Changed, ", ") + ")"
+	}
+	return line
+}
@@ -4,14 +4,92 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 )
 
 // Config represents the application configuration.
 type Config struct {
-	IdeasDirectory string `toml:"ideas_directory"`
-	Editor         string `toml:"editor"`
+	IdeasDirectory string          `toml:"ideas_directory"`
+	Editor         string          `toml:"editor"`
+	Views          map[string]View `toml:"views"`
+
+	// Hooks maps lifecycle events such as post-state-change to the shell
+	// commands run for them, configured as a [hooks] table.
+	Hooks map[string][]string `toml:"hooks"`
+
+	Rules []Rule `toml:"rules"`
+}
+
+// View is a saved list query, configured as a [views.NAME] table:
+//
+//	[views.pipeline]
+//	description = "Beliefs at work still being weighed"
+//	kind = "belief"
+//	state = "considering"
+//	tag = "work"
+//	query = "-tag:old"
+//	sort = "modified"
+//	columns = ["index", "state", "title", "purpose"]
+//
+// Kind, State, Tag and Purpose take the same values as the matching list
+// flags; Query is a list -q query.
+type View struct {
+	Description string   `toml:"description" json:"description,omitempty"`
+	Kind        string   `toml:"kind" json:"kind,omitempty"`
+	State       string   `toml:"state" json:"state,omitempty"`
+	Tag         string   `toml:"tag" json:"tag,omitempty"`
+	Purpose     string   `toml:"purpose" json:"purpose,omitempty"`
+	Query       string   `toml:"query" json:"query,omitempty"`
+	Sort        string   `toml:"sort" json:"sort,omitempty"`
+	Reverse     bool     `toml:"reverse" json:"reverse,omitempty"`
+	Columns     []string `toml:"columns" json:"columns,omitempty"`
+}
+
+// Rule is an automation rule applied when an idea is edited, configured
+// as a [[rules]] table:
+//
+//	[[rules]]
+//	name = "health ideas serve health"
+//	when_tag_added = "health"
+//	set_purpose = "Stay healthy"
+//
+// Exactly one when_ key says what triggers the rule: a tag being added,
+// the state becoming a value, or the kind becoming a value. The set_ and
+// add_ keys are the changes it makes. States accept display labels.
+type Rule struct {
+	Name         string `toml:"name"`
+	WhenTagAdded string `toml:"when_tag_added"`
+	WhenState    string `toml:"when_state"`
+	WhenKind     string `toml:"when_kind"`
+	SetPurpose   string `toml:"set_purpose"`
+	SetState     string `toml:"set_state"`
+	SetMaturity  string `toml:"set_maturity"`
+	AddTag       string `toml:"add_tag"`
+}
+
+// ViewNames returns the configured view names, sorted.
+func (c *Config) ViewNames() []string {
+	names := make([]string, 0, len(c.Views))
+	for name := range c.Views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupView returns the named view.
+func (c *Config) LookupView(name string) (View, error) {
+	v, ok := c.Views[name]
+	if !ok {
+		if len(c.Views) == 0 {
+			return View{}, fmt.Errorf("unknown view %q: no views configured in %s", name, ConfigPath())
+		}
+		return View{}, fmt.Errorf("unknown view %q: use %s", name, strings.Join(c.ViewNames(), ", "))
+	}
+	return v, nil
 }
 
 // DefaultConfig returns default configuration.
@@ -19,10 +97,21 @@ func DefaultConfig() *Config {
 	homeDir, _ := os.UserHomeDir()
 	return &Config{
 		IdeasDirectory: filepath.Join(homeDir, "ideas"),
-		Editor:         "vim",
 	}
 }
 
+// EditorCommand returns the command that opens a file for editing: the
+// editor setting, else $EDITOR, else vi. The setting may include arguments,
+// e.g. "code --wait"; one that is only whitespace counts as unset.
+func (c *Config) EditorCommand() []string {
+	for _, editor := range []string{c.Editor, os.Getenv("EDITOR")} {
+		if fields := strings.Fields(editor); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
 // Load reads configuration from a file. If path is empty, searches standard locations.
 func Load(path string) (*Config, error) {
 	cfg := DefaultConfig()
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -12,8 +13,28 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.IdeasDirectory == "" {
 		t.Error("IdeasDirectory should not be empty")
 	}
-	if cfg.Editor != "vim" {
-		t.Errorf("Editor: got %q, want %q", cfg.Editor, "vim")
+	if cfg.Editor != "" {
+		t.Errorf("Editor: got %q, want it empty so $EDITOR applies", cfg.Editor)
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	tests := []struct {
+		setting, env string
+		want         string
+	}{
+		{"code --wait", "nano", "[code --wait]"},
+		{"", "nano", "[nano]"},
+		{"   ", "emacs -nw", "[emacs -nw]"},
+		{"", "", "[vi]"},
+		{" ", " ", "[vi]"},
+	}
+	for _, tt := range tests {
+		t.Setenv("EDITOR", tt.env)
+		cfg := &Config{Editor: tt.setting}
+		if got := fmt.Sprint(cfg.EditorCommand()); got != tt.want {
+			t.Errorf("EditorCommand(%q, $EDITOR=%q) = %s, want %s", tt.setting, tt.env, got, tt.want)
+		}
 	}
 }
 
@@ -41,6 +62,45 @@ editor = "nano"
 	}
 }
 
+func TestLoad_Views(t *testing.T) {
+	dir := t.TempDir()
+
+	configContent := `ideas_directory = "` + dir + `"
+
+[views.pipeline]
+kind = "belief"
+state = "considering"
+tag = "work"
+sort = "title"
+columns = ["index", "title"]
+
+[views.today]
+query = "planned_for:today"
+`
+	configPath := filepath.Join(dir, "config.toml")
+	os.WriteFile(configPath, []byte(configContent), 0644)
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	names := cfg.ViewNames()
+	if len(names) != 2 || names[0] != "pipeline" || names[1] != "today" {
+		t.Errorf("ViewNames: got %v", names)
+	}
+	v, err := cfg.LookupView("pipeline")
+	if err != nil {
+		t.Fatalf("LookupView: %v", err)
+	}
+	if v.Kind != "belief" || v.State != "considering" || v.Tag != "work" || v.Sort != "title" || len(v.Columns) != 2 {
+		t.Errorf("pipeline view: got %+v", v)
+	}
+	if _, err := cfg.LookupView("missing"); err == nil {
+		t.Error("expected error for unknown view")
+	}
+}
+
 func TestLoad_NonexistentFile(t *testing.T) {
 	cfg, err := Load("/nonexistent/path/config.toml")
 	if err != nil {
@@ -128,3 +188,19 @@ func TestConfigPath(t *testing.T) {
 		t.Error("ConfigPath should return a non-empty path")
 	}
 }
+
+func TestUIState_RoundTrip(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if s := LoadUIState(); s != (UIState{}) {
+		t.Errorf("LoadUIState with no file: got %+v, want zero", s)
+	}
+
+	want := UIState{SortBy: "title", ReverseSort: true}
+	if err := SaveUIState(want); err != nil {
+		t.Fatalf("SaveUIState: %v", err)
+	}
+	if got := LoadUIState(); got != want {
+		t.Errorf("LoadUIState: got %+v, want %+v", got, want)
+	}
+}
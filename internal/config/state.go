@@ -0,0 +1,61 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// UIState is TUI state remembered between sessions. It lives beside the
+// config file in state.json.
+type UIState struct {
+	SortBy      string `json:"sort_by,omitempty"`
+	ReverseSort bool   `json:"reverse_sort,omitempty"`
+}
+
+// StatePath returns the path of the TUI state file.
+func StatePath() string {
+	path := ConfigPath()
+	if path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(path), "state.json")
+}
+
+// LoadUIState reads the TUI state file. A missing or unreadable file yields
+// the zero state.
+func LoadUIState() UIState {
+	var s UIState
+	path := StatePath()
+	if path == "" {
+		return s
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return UIState{}
+	}
+	return s
+}
+
+// SaveUIState writes the TUI state file, creating its directory if needed.
+func SaveUIState(s UIState) error {
+	path := StatePath()
+	if path == "" {
+		return fmt.Errorf("no config directory for state file")
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package denote
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/mph-llm-experiments/acore"
+)
+
+const (
+	indexFilename = ".anote-index.json"
+	indexVersion  = 2
+)
+
+// IndexEntry is the cached metadata for a single idea file. ModTime and Size
+// are compared against the file on disk to detect out-of-band edits.
+type IndexEntry struct {
+	File        string `json:"file"`
+	ID          string `json:"id"`
+	IndexID     int    `json:"index_id"`
+	Title       string `json:"title"`
+	Kind        string `json:"kind,omitempty"`
+	State       string `json:"state,omitempty"`
+	PurposeID   string `json:"purpose_id,omitempty"`
+	PurposeName string `json:"purpose_name,omitempty"`
+	ModTime     int64  `json:"mtime"`
+	Size        int64  `json:"size"`
+}
+
+// ideaIndex is the on-disk metadata index stored in .anote-index.json.
+// Entries are keyed by filename; lookups by ULID and index_id go through
+// the in-memory maps built on load.
+type ideaIndex struct {
+	Version int                    `json:"version"`
+	Entries map[string]*IndexEntry `json:"entries"`
+
+	byID      map[string]*IndexEntry
+	byIndexID map[int]*IndexEntry
+	dirty     bool
+}
+
+// loadIndex reads the index from dir. A missing, unreadable or outdated index
+// yields an empty one, which is then rebuilt on the next refresh.
+func loadIndex(dir string) *ideaIndex {
+	ix := &ideaIndex{Version: indexVersion, Entries: map[string]*IndexEntry{}}
+	data, err := os.ReadFile(filepath.Join(dir, indexFilename))
+	if err == nil {
+		var onDisk ideaIndex
+		if json.Unmarshal(data, &onDisk) == nil && onDisk.Version == indexVersion && onDisk.Entries != nil {
+			ix.Entries = onDisk.Entries
+		} else {
+			ix.dirty = true
+		}
+	}
+	ix.reindex()
+	return ix
+}
+
+// save writes the index back to dir if it changed. The write goes through a
+// temp file and rename so concurrent readers never see a partial index.
+func (ix *ideaIndex) save(dir string) error {
+	if !ix.dirty {
+		return nil
+	}
+	data, err := json.Marshal(ix)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, indexFilename+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, indexFilename)); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	ix.dirty = false
+	return nil
+}
+
+// reindex rebuilds the ULID and index_id lookup maps from Entries.
+func (ix *ideaIndex) reindex() {
+	ix.byID = make(map[string]*IndexEntry, len(ix.Entries))
+	ix.byIndexID = make(map[int]*IndexEntry, len(ix.Entries))
+	for _, e := range ix.Entries {
+		ix.byID[e.ID] = e
+		ix.byIndexID[e.IndexID] = e
+	}
+}
+
+// put records a freshly parsed idea and its file info.
+func (ix *ideaIndex) put(idea *Idea, info os.FileInfo) {
+	name := filepath.Base(idea.FilePath)
+	e := &IndexEntry{
+		File:        name,
+		ID:          idea.ID,
+		IndexID:     idea.IndexID,
+		Title:       idea.Title,
+		Kind:        idea.Kind,
+		State:       idea.State,
+		PurposeID:   idea.PurposeID,
+		PurposeName: idea.PurposeName,
+		ModTime:     info.ModTime().UnixNano(),
+		Size:        info.Size(),
+	}
+	old, existed := ix.Entries[name]
+	if existed && *old == *e {
+		return
+	}
+	ix.Entries[name] = e
+	ix.dirty = true
+	if existed && (old.ID != e.ID || old.IndexID != e.IndexID) {
+		ix.reindex()
+		return
+	}
+	ix.byID[e.ID] = e
+	ix.byIndexID[e.IndexID] = e
+}
+
+// remove drops the entry for a file that no longer exists.
+func (ix *ideaIndex) remove(name string) {
+	if _, ok := ix.Entries[name]; !ok {
+		return
+	}
+	delete(ix.Entries, name)
+	ix.reindex()
+	ix.dirty = true
+}
+
+// current reports whether the entry still matches the file on disk.
+func (e *IndexEntry) current(info os.FileInfo) bool {
+	return e.ModTime == info.ModTime().UnixNano() && e.Size == info.Size()
+}
+
+// refresh brings the index in line with the directory: new and changed files
+// are re-parsed, unchanged files are only stat'ed, and deleted files dropped.
+func (ix *ideaIndex) refresh(dir string) error {
+	sc := &acore.Scanner{Store: acore.NewLocalStore(dir)}
+	names, err := sc.FindByType(TypeIdea)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		seen[name] = true
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if e, ok := ix.Entries[name]; ok && e.current(info) {
+			continue
+		}
+		idea, err := ParseIdeaFile(path)
+		if err != nil {
+			ix.remove(name)
+			continue
+		}
+		ix.put(idea, info)
+	}
+
+	for name := range ix.Entries {
+		if !seen[name] {
+			ix.remove(name)
+		}
+	}
+	return nil
+}
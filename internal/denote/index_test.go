@@ -0,0 +1,181 @@
+package denote
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestIdea writes a minimal idea file and returns its path.
+func writeTestIdea(t *testing.T, dir, id string, indexID int, title string) string {
+	t.Helper()
+	content := fmt.Sprintf("---\nid: %s\ntitle: %s\nindex_id: %d\ntype: idea\nstate: seed\n---\n", id, title, indexID)
+	path := filepath.Join(dir, id+"--test__idea.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+	return path
+}
+
+func TestScanner_FindByIndexID_BuildsIndex(t *testing.T) {
+	dir := t.TempDir()
+	writeTestIdea(t, dir, "01TESTID0000000000000000A1", 1, "First")
+	writeTestIdea(t, dir, "01TESTID0000000000000000A2", 2, "Second")
+
+	s := NewScanner(dir)
+	idea, err := s.FindByIndexID(2)
+	if err != nil {
+		t.Fatalf("FindByIndexID: %v", err)
+	}
+	if idea == nil || idea.Title != "Second" {
+		t.Fatalf("FindByIndexID(2): got %+v, want Second", idea)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, indexFilename)); err != nil {
+		t.Errorf("expected %s to be written: %v", indexFilename, err)
+	}
+
+	ix := loadIndex(dir)
+	if len(ix.Entries) != 2 {
+		t.Errorf("index entries: got %d, want 2", len(ix.Entries))
+	}
+}
+
+func TestScanner_FindByEntityID_NotFound(t *testing.T) {
+	dir := t.TempDir()
+	writeTestIdea(t, dir, "01TESTID0000000000000000A1", 1, "First")
+
+	idea, err := NewScanner(dir).FindByEntityID("01TESTID0000000000000000ZZ")
+	if err != nil {
+		t.Fatalf("FindByEntityID: %v", err)
+	}
+	if idea != nil {
+		t.Errorf("expected nil for unknown ID, got %q", idea.Title)
+	}
+}
+
+func TestScanner_Lookup_PicksUpNewFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTestIdea(t, dir, "01TESTID0000000000000000A1", 1, "First")
+
+	s := NewScanner(dir)
+	if _, err := s.FindByIndexID(1); err != nil {
+		t.Fatalf("FindByIndexID: %v", err)
+	}
+
+	// A file added out-of-band (e.g. by sync) is not in the index yet.
+	writeTestIdea(t, dir, "01TESTID0000000000000000A2", 7, "Synced")
+
+	idea, err := s.FindByIndexID(7)
+	if err != nil {
+		t.Fatalf("FindByIndexID: %v", err)
+	}
+	if idea == nil || idea.Title != "Synced" {
+		t.Fatalf("expected to find synced idea, got %+v", idea)
+	}
+}
+
+func TestScanner_Lookup_DetectsDrift(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestIdea(t, dir, "01TESTID0000000000000000A1", 1, "Original")
+
+	s := NewScanner(dir)
+	if _, err := s.FindByIndexID(1); err != nil {
+		t.Fatalf("FindByIndexID: %v", err)
+	}
+
+	// Rewrite the file with a new index_id; the stale entry must not match.
+	content := "---\nid: 01TESTID0000000000000000A1\ntitle: Renumbered\nindex_id: 9\ntype: idea\n---\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(path, future, future)
+
+	idea, err := s.FindByIndexID(1)
+	if err != nil {
+		t.Fatalf("FindByIndexID: %v", err)
+	}
+	if idea != nil {
+		t.Errorf("expected index_id 1 to be gone, got %q", idea.Title)
+	}
+
+	idea, err = s.FindByIndexID(9)
+	if err != nil {
+		t.Fatalf("FindByIndexID: %v", err)
+	}
+	if idea == nil || idea.Title != "Renumbered" {
+		t.Errorf("expected renumbered idea, got %+v", idea)
+	}
+}
+
+func TestScanner_Entries_DropsDeletedFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTestIdea(t, dir, "01TESTID0000000000000000A1", 1, "Keep")
+	gone := writeTestIdea(t, dir, "01TESTID0000000000000000A2", 2, "Gone")
+
+	s := NewScanner(dir)
+	if _, err := s.FindIdeas(); err != nil {
+		t.Fatalf("FindIdeas: %v", err)
+	}
+	if err := os.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := s.Entries()
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Title != "Keep" {
+		t.Errorf("Entries: got %+v, want only Keep", entries)
+	}
+}
+
+func TestScanner_EachIdea_StopsEarly(t *testing.T) {
+	dir := t.TempDir()
+	writeTestIdea(t, dir, "01TESTID0000000000000000A1", 1, "First")
+	writeTestIdea(t, dir, "01TESTID0000000000000000A2", 2, "Second")
+
+	s := NewScanner(dir)
+	if _, err := s.FindIdeas(); err != nil {
+		t.Fatalf("FindIdeas: %v", err)
+	}
+
+	calls := 0
+	err := s.EachIdea(func(*Idea) bool {
+		calls++
+		return false
+	})
+	if err != nil {
+		t.Fatalf("EachIdea: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("EachIdea: fn called %d times after returning false, want 1", calls)
+	}
+
+	// A partial pass must not drop the ideas it never reached from the index
+	if ix := loadIndex(dir); len(ix.Entries) != 2 {
+		t.Errorf("index entries after partial scan: got %d, want 2", len(ix.Entries))
+	}
+}
+
+func TestLoadIndex_CorruptFileRebuilds(t *testing.T) {
+	dir := t.TempDir()
+	writeTestIdea(t, dir, "01TESTID0000000000000000A1", 1, "First")
+	if err := os.WriteFile(filepath.Join(dir, indexFilename), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	idea, err := NewScanner(dir).FindByIndexID(1)
+	if err != nil {
+		t.Fatalf("FindByIndexID: %v", err)
+	}
+	if idea == nil || idea.Title != "First" {
+		t.Fatalf("expected lookup to rebuild index, got %+v", idea)
+	}
+	if ix := loadIndex(dir); len(ix.Entries) != 1 {
+		t.Errorf("expected rebuilt index with 1 entry, got %d", len(ix.Entries))
+	}
+}
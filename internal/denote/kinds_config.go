@@ -2,24 +2,44 @@ package denote
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"unicode/utf8"
 )
 
 const kindsConfigFilename = "kinds.json"
 
 // KindEntry defines the valid states and behavior for a single kind.
+// Transitions is optional: when set, it maps each state to the states it may
+// move to, and states without an entry have no outbound transitions.
+// Labels maps canonical states to kind-specific display labels; Abbrev is the
+// one-letter code used in the CLI table and Symbol the TUI list marker.
 type KindEntry struct {
-	States          []string `json:"states"`
-	Terminal        []string `json:"terminal"`
-	Default         string   `json:"default"`
-	PurposeRequired bool     `json:"purpose_required"`
+	States          []string            `json:"states"`
+	Terminal        []string            `json:"terminal"`
+	Default         string              `json:"default"`
+	PurposeRequired bool                `json:"purpose_required"`
+	NoMaturity      bool                `json:"no_maturity,omitempty"`
+	Transitions     map[string][]string `json:"transitions,omitempty"`
+	Labels          map[string]string   `json:"labels,omitempty"`
+	Abbrev          string              `json:"abbrev,omitempty"`
+	Symbol          string              `json:"symbol,omitempty"`
 }
 
+// Modes for creating an idea of a purpose-required kind without a purpose.
+const (
+	PurposeWarn = "warn"
+	PurposeFail = "fail"
+)
+
 // KindsConfig is the full configuration loaded from kinds.json.
+// PurposeOnCreate is PurposeWarn (the default when empty) or PurposeFail.
 type KindsConfig struct {
-	Kinds map[string]KindEntry `json:"kinds"`
+	Kinds           map[string]KindEntry `json:"kinds"`
+	PurposeOnCreate string               `json:"purpose_on_create,omitempty"`
 }
 
 // DefaultKindsConfig returns the built-in default configuration.
@@ -28,45 +48,75 @@ func DefaultKindsConfig() *KindsConfig {
 	return &KindsConfig{
 		Kinds: map[string]KindEntry{
 			KindAspiration: {
-				States:          []string{StateSeed, StateDraft, StateActive, StateIterating, StateImplemented, StateDropped},
-				Terminal:        []string{StateImplemented, StateDropped},
+				States:          []string{StateSeed, StateDraft, StateActive, StateIterating, StateImplemented, StateArchived, StateRejected, StateDropped},
+				Terminal:        []string{StateImplemented, StateArchived, StateRejected, StateDropped},
 				Default:         StateSeed,
 				PurposeRequired: true,
+				Abbrev:          "A",
+				Symbol:          "↑",
 			},
 			KindBelief: {
 				States:          []string{StateSeed, StateActive, StateIterating, StateImplemented, StateRejected},
 				Terminal:        []string{StateImplemented, StateRejected},
 				Default:         StateSeed,
 				PurposeRequired: true,
+				Labels: map[string]string{
+					StateActive:      "considering",
+					StateIterating:   "reconsidering",
+					StateImplemented: "accepted",
+				},
+				Abbrev: "B",
+				Symbol: "◆",
 			},
 			KindPlan: {
-				States:          []string{StateSeed, StateDraft, StateActive, StateIterating, StateImplemented, StateDropped},
-				Terminal:        []string{StateImplemented, StateDropped},
+				States:          []string{StateSeed, StateDraft, StateActive, StateIterating, StateImplemented, StateArchived, StateRejected, StateDropped},
+				Terminal:        []string{StateImplemented, StateArchived, StateRejected, StateDropped},
 				Default:         StateSeed,
 				PurposeRequired: true,
+				Labels: map[string]string{
+					StateActive:      "committed",
+					StateIterating:   "replanning",
+					StateImplemented: "completed",
+				},
+				Abbrev: "P",
+				Symbol: "→",
 			},
 			KindNote: {
 				States:          []string{StateActive, StateArchived},
 				Terminal:        []string{StateArchived},
 				Default:         StateActive,
 				PurposeRequired: false,
+				NoMaturity:      true,
+				Abbrev:          "N",
+				Symbol:          "·",
 			},
 			KindFact: {
 				States:          []string{StateActive, StateArchived},
 				Terminal:        []string{StateArchived},
 				Default:         StateActive,
 				PurposeRequired: false,
+				NoMaturity:      true,
+				Abbrev:          "F",
+				Symbol:          "=",
 			},
 			KindPurpose: {
 				States:          []string{StateActive, StateArchived},
 				Terminal:        []string{StateArchived},
 				Default:         StateActive,
 				PurposeRequired: false,
+				NoMaturity:      true,
+				Abbrev:          "U",
+				Symbol:          "★",
 			},
 		},
 	}
 }
 
+// builtinKinds is the default configuration. It backs the package-level
+// DisplayState helpers and supplies display fields for built-in kinds when
+// an older kinds.json predates them.
+var builtinKinds = DefaultKindsConfig()
+
 // LoadKindsConfig reads kinds.json from dir. If missing, writes defaults and returns them.
 func LoadKindsConfig(dir string) (*KindsConfig, error) {
 	path := filepath.Join(dir, kindsConfigFilename)
@@ -129,6 +179,149 @@ func (kc *KindsConfig) DefaultStateFor(kind string) string {
 	return StateSeed
 }
 
+// ValidateTransition checks a state change against the kind's transition graph.
+// Kinds without a graph allow any move between their valid states.
+func (kc *KindsConfig) ValidateTransition(kind, from, to string) error {
+	if !kc.IsCompliant(kind, to) {
+		return fmt.Errorf("state %q is not valid for kind %s", to, kind)
+	}
+	if from == to {
+		return nil
+	}
+	entry := kc.Kinds[kind]
+	if entry.Transitions == nil {
+		return nil
+	}
+	allowed := entry.Transitions[from]
+	if len(allowed) == 0 {
+		return fmt.Errorf("no transitions allowed from %q for kind %s", from, kind)
+	}
+	for _, s := range allowed {
+		if s == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("transition from %q to %q is not allowed for kind %s", from, to, kind)
+}
+
+// NextStates returns the states reachable from `from` in one step, in the
+// order they are listed in the kind's states. Kinds without a transition
+// graph return all of their valid states.
+func (kc *KindsConfig) NextStates(kind, from string) []string {
+	entry, ok := kc.Kinds[kind]
+	if !ok {
+		return nil
+	}
+	if entry.Transitions == nil {
+		return entry.States
+	}
+	var next []string
+	for _, s := range entry.States {
+		if s != from && kc.ValidateTransition(kind, from, s) == nil {
+			next = append(next, s)
+		}
+	}
+	return next
+}
+
+// labelsFor returns the display labels for kind, falling back to the
+// built-in labels when a built-in kind's entry does not define any.
+func (kc *KindsConfig) labelsFor(kind string) map[string]string {
+	if entry, ok := kc.Kinds[kind]; ok && entry.Labels != nil {
+		return entry.Labels
+	}
+	if kc != builtinKinds {
+		return builtinKinds.labelsFor(kind)
+	}
+	return nil
+}
+
+// DisplayState returns the kind-specific display label for a canonical state.
+func (kc *KindsConfig) DisplayState(canonical, kind string) string {
+	if label, ok := kc.labelsFor(kind)[canonical]; ok && label != "" {
+		return label
+	}
+	return canonical
+}
+
+// ResolveDisplayState converts a display label (possibly kind-specific) back
+// to the canonical state value. Returns the canonical state and the kind it
+// belongs to (empty string if the label is shared or already canonical).
+func (kc *KindsConfig) ResolveDisplayState(display string) (canonical string, matchedKind string) {
+	if IsValidState(display) || kc.HasState(display) {
+		return display, ""
+	}
+	for _, kind := range kc.AllKinds() {
+		for canonical, label := range kc.labelsFor(kind) {
+			if label == display {
+				return canonical, kind
+			}
+		}
+	}
+	return display, ""
+}
+
+// Abbrev returns the one-letter abbreviation for kind. Kinds without one
+// fall back to the built-in abbreviation, then to their uppercased initial.
+func (kc *KindsConfig) Abbrev(kind string) string {
+	if entry, ok := kc.Kinds[kind]; ok && entry.Abbrev != "" {
+		return entry.Abbrev
+	}
+	if entry, ok := builtinKinds.Kinds[kind]; ok {
+		return entry.Abbrev
+	}
+	if kind == "" {
+		return "?"
+	}
+	r, _ := utf8.DecodeRuneInString(kind)
+	return strings.ToUpper(string(r))
+}
+
+// Symbol returns the TUI list symbol for kind, falling back to the built-in
+// symbol and then to "?".
+func (kc *KindsConfig) Symbol(kind string) string {
+	if entry, ok := kc.Kinds[kind]; ok && entry.Symbol != "" {
+		return entry.Symbol
+	}
+	if entry, ok := builtinKinds.Kinds[kind]; ok {
+		return entry.Symbol
+	}
+	return "?"
+}
+
+// IsTerminal returns true if state is one of kind's terminal states.
+// Unknown kinds have no terminal states.
+func (kc *KindsConfig) IsTerminal(kind, state string) bool {
+	entry, ok := kc.Kinds[kind]
+	if !ok {
+		return false
+	}
+	for _, s := range entry.Terminal {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// UsesMaturity returns true if ideas of this kind track maturity.
+func (kc *KindsConfig) UsesMaturity(kind string) bool {
+	entry, ok := kc.Kinds[kind]
+	return ok && !entry.NoMaturity
+}
+
+// HasState returns true if any kind in the config lists state as valid.
+func (kc *KindsConfig) HasState(state string) bool {
+	for _, entry := range kc.Kinds {
+		for _, s := range entry.States {
+			if s == state {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // PurposeRequired returns true if ideas of this kind require a purpose.
 func (kc *KindsConfig) PurposeRequired(kind string) bool {
 	if entry, ok := kc.Kinds[kind]; ok {
@@ -137,6 +330,26 @@ func (kc *KindsConfig) PurposeRequired(kind string) bool {
 	return false
 }
 
+// PurposeRequiredFor returns true if an idea of kind must have a purpose to
+// be in state. Seeds may be captured without one (subject to
+// PurposeOnCreate), and ideas can always be archived, rejected or dropped.
+func (kc *KindsConfig) PurposeRequiredFor(kind, state string) bool {
+	if !kc.PurposeRequired(kind) {
+		return false
+	}
+	switch state {
+	case StateSeed, StateArchived, StateRejected, StateDropped:
+		return false
+	}
+	return true
+}
+
+// FailOnMissingPurpose returns true if creating an idea of a
+// purpose-required kind without a purpose is an error rather than a warning.
+func (kc *KindsConfig) FailOnMissingPurpose() bool {
+	return kc.PurposeOnCreate == PurposeFail
+}
+
 // KindExists returns true if kind is in the config.
 func (kc *KindsConfig) KindExists(kind string) bool {
 	_, ok := kc.Kinds[kind]
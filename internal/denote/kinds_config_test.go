@@ -60,6 +60,8 @@ func TestKindsConfig_IsCompliant(t *testing.T) {
 		{"aspiration", "seed", true},
 		{"aspiration", "active", true},
 		{"aspiration", "considering", false}, // belief display label, not canonical
+		{"aspiration", "rejected", true},
+		{"plan", "archived", true},
 		{"belief", "seed", true},
 		{"belief", "active", true},
 		{"note", "active", true},
@@ -137,3 +139,196 @@ func TestKindsConfig_KindExists(t *testing.T) {
 		t.Error("expected bogus to not exist")
 	}
 }
+
+func TestKindsConfig_IsTerminal(t *testing.T) {
+	cfg := denote.DefaultKindsConfig()
+
+	tests := []struct {
+		kind, state string
+		want        bool
+	}{
+		{"aspiration", "implemented", true},
+		{"aspiration", "dropped", true},
+		{"aspiration", "archived", true},
+		{"plan", "rejected", true},
+		{"aspiration", "active", false},
+		{"note", "archived", true},
+		{"note", "active", false},
+		{"unknown_kind", "archived", false},
+	}
+
+	for _, tt := range tests {
+		if got := cfg.IsTerminal(tt.kind, tt.state); got != tt.want {
+			t.Errorf("IsTerminal(%q, %q) = %v, want %v", tt.kind, tt.state, got, tt.want)
+		}
+	}
+}
+
+func TestKindsConfig_UsesMaturity(t *testing.T) {
+	cfg := denote.DefaultKindsConfig()
+	if !cfg.UsesMaturity("aspiration") {
+		t.Error("expected aspiration to use maturity")
+	}
+	if cfg.UsesMaturity("fact") {
+		t.Error("expected fact to not use maturity")
+	}
+	if cfg.UsesMaturity("unknown") {
+		t.Error("expected unknown kind to not use maturity")
+	}
+}
+
+func TestKindsConfig_CustomKindFromFile(t *testing.T) {
+	dir := t.TempDir()
+	data := `{"kinds": {"decision": {"states": ["active", "implemented"], "terminal": ["implemented"], "default": "active"}}}`
+	if err := os.WriteFile(filepath.Join(dir, "kinds.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := denote.LoadKindsConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadKindsConfig: %v", err)
+	}
+	if !cfg.KindExists("decision") {
+		t.Error("expected custom kind 'decision' to exist")
+	}
+	if cfg.KindExists("aspiration") {
+		t.Error("expected kinds.json to replace the built-in kinds")
+	}
+	if !cfg.HasState("implemented") || cfg.HasState("seed") {
+		t.Error("HasState should reflect only the states in kinds.json")
+	}
+	if !cfg.UsesMaturity("decision") {
+		t.Error("expected maturity to default on for custom kinds")
+	}
+}
+
+func TestKindsConfig_ValidateTransition_NoGraph(t *testing.T) {
+	cfg := denote.DefaultKindsConfig()
+	if err := cfg.ValidateTransition("aspiration", "seed", "implemented"); err != nil {
+		t.Errorf("expected any move without a graph, got: %v", err)
+	}
+	if err := cfg.ValidateTransition("note", "active", "seed"); err == nil {
+		t.Error("expected error for state not valid for kind")
+	}
+}
+
+func TestKindsConfig_ValidateTransition_Graph(t *testing.T) {
+	cfg := denote.DefaultKindsConfig()
+	entry := cfg.Kinds["aspiration"]
+	entry.Transitions = map[string][]string{
+		"seed":   {"draft"},
+		"draft":  {"active", "dropped"},
+		"active": {"implemented"},
+	}
+	cfg.Kinds["aspiration"] = entry
+
+	tests := []struct {
+		from, to string
+		wantErr  bool
+	}{
+		{"seed", "draft", false},
+		{"draft", "active", false},
+		{"active", "active", false}, // no-op is always allowed
+		{"seed", "implemented", true},
+		{"implemented", "active", true}, // no outbound edges
+		{"draft", "bogus", true},
+	}
+	for _, tt := range tests {
+		err := cfg.ValidateTransition("aspiration", tt.from, tt.to)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateTransition(%q, %q): got err=%v, wantErr=%v", tt.from, tt.to, err, tt.wantErr)
+		}
+	}
+
+	next := cfg.NextStates("aspiration", "draft")
+	if len(next) != 2 || next[0] != "active" || next[1] != "dropped" {
+		t.Errorf("NextStates(draft): got %v, want [active dropped]", next)
+	}
+	if next := cfg.NextStates("aspiration", "implemented"); len(next) != 0 {
+		t.Errorf("NextStates(implemented): got %v, want none", next)
+	}
+	if next := cfg.NextStates("note", "active"); len(next) != 2 {
+		t.Errorf("NextStates without graph: got %v, want all note states", next)
+	}
+}
+
+func TestKindsConfig_DisplayState_CustomLabels(t *testing.T) {
+	cfg := denote.DefaultKindsConfig()
+	cfg.Kinds["decision"] = denote.KindEntry{
+		States: []string{"draft", "active", "implemented"},
+		Labels: map[string]string{"active": "deciding", "implemented": "decided"},
+	}
+
+	if got := cfg.DisplayState("active", "decision"); got != "deciding" {
+		t.Errorf("DisplayState(active, decision) = %q, want deciding", got)
+	}
+	if got := cfg.DisplayState("draft", "decision"); got != "draft" {
+		t.Errorf("DisplayState(draft, decision) = %q, want draft", got)
+	}
+
+	canon, kind := cfg.ResolveDisplayState("decided")
+	if canon != "implemented" || kind != "decision" {
+		t.Errorf("ResolveDisplayState(decided) = (%q, %q), want (implemented, decision)", canon, kind)
+	}
+}
+
+func TestKindsConfig_DisplayFields_FallBackForOlderFiles(t *testing.T) {
+	dir := t.TempDir()
+	// A kinds.json written before labels, abbrev and symbol existed.
+	data := `{"kinds": {
+		"belief": {"states": ["seed", "active"], "terminal": [], "default": "seed"},
+		"question": {"states": ["active"], "terminal": [], "default": "active"},
+		"énigme": {"states": ["active"], "terminal": [], "default": "active"}
+	}}`
+	if err := os.WriteFile(filepath.Join(dir, "kinds.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := denote.LoadKindsConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadKindsConfig: %v", err)
+	}
+
+	if got := cfg.DisplayState("active", "belief"); got != "considering" {
+		t.Errorf("DisplayState(active, belief) = %q, want built-in label considering", got)
+	}
+	if got := cfg.Abbrev("belief"); got != "B" {
+		t.Errorf("Abbrev(belief) = %q, want B", got)
+	}
+	if got := cfg.Abbrev("question"); got != "Q" {
+		t.Errorf("Abbrev(question) = %q, want Q", got)
+	}
+	if got := cfg.Abbrev("énigme"); got != "É" {
+		t.Errorf("Abbrev(énigme) = %q, want É", got)
+	}
+	if got := cfg.Symbol("question"); got != "?" {
+		t.Errorf("Symbol(question) = %q, want ?", got)
+	}
+}
+
+func TestKindsConfig_Abbrev_Purpose(t *testing.T) {
+	cfg := denote.DefaultKindsConfig()
+	if got := cfg.Abbrev("purpose"); got == "A" {
+		t.Error("purpose should not share aspiration's abbreviation")
+	}
+}
+
+func TestKindsConfig_PurposeRequiredFor(t *testing.T) {
+	cfg := denote.DefaultKindsConfig()
+
+	tests := []struct {
+		kind, state string
+		want        bool
+	}{
+		{"aspiration", "seed", false},
+		{"aspiration", "draft", true},
+		{"aspiration", "active", true},
+		{"aspiration", "dropped", false},
+		{"belief", "implemented", true},
+		{"note", "active", false},
+	}
+	for _, tt := range tests {
+		if got := cfg.PurposeRequiredFor(tt.kind, tt.state); got != tt.want {
+			t.Errorf("PurposeRequiredFor(%s, %s) = %v, want %v", tt.kind, tt.state, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package denote
+
+import (
+	"sort"
+	"strings"
+)
+
+// Purpose audit problems.
+const (
+	PurposeProblemMissing  = "missing"  // purpose-required kind with no purpose
+	PurposeProblemDangling = "dangling" // purpose_id does not name a purpose
+	PurposeProblemStale    = "stale"    // purpose_name differs from the purpose's title
+)
+
+// PurposeIssue is an idea whose purpose reference does not comply with kinds.json
+// or no longer matches the purpose it points at.
+type PurposeIssue struct {
+	ID          string `json:"id"`
+	IndexID     int    `json:"index_id"`
+	Title       string `json:"title"`
+	Kind        string `json:"kind"`
+	State       string `json:"state"`
+	Problem     string `json:"problem"`
+	PurposeID   string `json:"purpose_id,omitempty"`
+	PurposeName string `json:"purpose_name,omitempty"`
+}
+
+// AuditPurposes checks every idea's purpose reference and returns the
+// problems found, ordered by index_id:
+//
+//   - missing: a purpose-required kind has no purpose. Archived, rejected and
+//     dropped ideas are exempt; seeds are included because the requirement
+//     applies from capture.
+//   - dangling: purpose_id names an idea that does not exist or is not a purpose.
+//   - stale: purpose_name differs from the purpose's current title.
+func AuditPurposes(kc *KindsConfig, entries []IndexEntry) []PurposeIssue {
+	purposes := make(map[string]IndexEntry)
+	for _, e := range entries {
+		if e.Kind == KindPurpose {
+			purposes[e.ID] = e
+		}
+	}
+
+	var issues []PurposeIssue
+	for _, e := range entries {
+		kind := e.Kind
+		if kind == "" {
+			kind = KindAspiration
+		}
+
+		problem := ""
+		if e.PurposeID == "" {
+			if !kc.PurposeRequired(kind) {
+				continue
+			}
+			if e.State != StateSeed && !kc.PurposeRequiredFor(kind, e.State) {
+				continue
+			}
+			problem = PurposeProblemMissing
+		} else if p, ok := purposes[e.PurposeID]; !ok {
+			problem = PurposeProblemDangling
+		} else if e.PurposeName != p.Title {
+			problem = PurposeProblemStale
+		} else {
+			continue
+		}
+
+		issues = append(issues, PurposeIssue{
+			ID:          e.ID,
+			IndexID:     e.IndexID,
+			Title:       e.Title,
+			Kind:        kind,
+			State:       e.State,
+			Problem:     problem,
+			PurposeID:   e.PurposeID,
+			PurposeName: e.PurposeName,
+		})
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].IndexID < issues[j].IndexID })
+	return issues
+}
+
+// PurposeNode is a purpose in the hierarchy formed by attaching purposes to
+// other purposes: a purpose's own purpose_id names its parent.
+type PurposeNode struct {
+	Entry    IndexEntry
+	Children []*PurposeNode
+}
+
+// PurposeParents maps each purpose's ID to its parent purpose's ID. Purposes
+// whose purpose_id is empty or does not name a purpose are roots and map to "".
+func PurposeParents(entries []IndexEntry) map[string]string {
+	parents := make(map[string]string)
+	for _, e := range entries {
+		if e.Kind == KindPurpose {
+			parents[e.ID] = e.PurposeID
+		}
+	}
+	for id, parent := range parents {
+		if _, ok := parents[parent]; !ok {
+			parents[id] = ""
+		}
+	}
+	return parents
+}
+
+// PurposeSubtree returns the set holding id and every purpose beneath it.
+func PurposeSubtree(parents map[string]string, id string) map[string]bool {
+	children := make(map[string][]string)
+	for child, parent := range parents {
+		if parent != "" {
+			children[parent] = append(children[parent], child)
+		}
+	}
+	subtree := map[string]bool{id: true}
+	queue := []string{id}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, c := range children[cur] {
+			if !subtree[c] {
+				subtree[c] = true
+				queue = append(queue, c)
+			}
+		}
+	}
+	return subtree
+}
+
+// BuildPurposeTree arranges the purposes in entries into a forest, with
+// siblings sorted by title. Purposes caught in a parent cycle (from
+// out-of-band edits) are promoted to roots so every purpose appears once.
+func BuildPurposeTree(entries []IndexEntry) []*PurposeNode {
+	parents := PurposeParents(entries)
+	nodes := make(map[string]*PurposeNode)
+	for _, e := range entries {
+		if e.Kind == KindPurpose {
+			nodes[e.ID] = &PurposeNode{Entry: e}
+		}
+	}
+
+	var roots []*PurposeNode
+	for id, n := range nodes {
+		if parents[id] == "" {
+			roots = append(roots, n)
+		} else {
+			p := nodes[parents[id]]
+			p.Children = append(p.Children, n)
+		}
+	}
+
+	placed := make(map[string]bool)
+	var mark func(n *PurposeNode)
+	mark = func(n *PurposeNode) {
+		if placed[n.Entry.ID] {
+			return
+		}
+		placed[n.Entry.ID] = true
+		for _, c := range n.Children {
+			mark(c)
+		}
+	}
+	for _, r := range roots {
+		mark(r)
+	}
+	// Whatever is unreachable from a root sits on a cycle; cut it at its
+	// lowest index_id and treat that purpose as a root.
+	for {
+		var cut *PurposeNode
+		for id, n := range nodes {
+			if !placed[id] && (cut == nil || n.Entry.IndexID < cut.Entry.IndexID) {
+				cut = n
+			}
+		}
+		if cut == nil {
+			break
+		}
+		if p := nodes[parents[cut.Entry.ID]]; p != nil {
+			for i, c := range p.Children {
+				if c == cut {
+					p.Children = append(p.Children[:i], p.Children[i+1:]...)
+					break
+				}
+			}
+		}
+		roots = append(roots, cut)
+		mark(cut)
+	}
+
+	var sortNodes func(ns []*PurposeNode)
+	sortNodes = func(ns []*PurposeNode) {
+		sort.Slice(ns, func(i, j int) bool {
+			return strings.ToLower(ns[i].Entry.Title) < strings.ToLower(ns[j].Entry.Title)
+		})
+		for _, n := range ns {
+			sortNodes(n.Children)
+		}
+	}
+	sortNodes(roots)
+	return roots
+}
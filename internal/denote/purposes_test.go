@@ -0,0 +1,111 @@
+package denote_test
+
+import (
+	"testing"
+
+	"github.com/mph-llm-experiments/anote/internal/denote"
+)
+
+func TestAuditPurposes(t *testing.T) {
+	cfg := denote.DefaultKindsConfig()
+	entries := []denote.IndexEntry{
+		{ID: "p", IndexID: 1, Title: "Purpose", Kind: "purpose", State: "active"},
+		{ID: "a", IndexID: 4, Title: "Attached", Kind: "aspiration", State: "active", PurposeID: "p", PurposeName: "Purpose"},
+		{ID: "b", IndexID: 3, Title: "Loose seed", State: "seed"},
+		{ID: "c", IndexID: 2, Title: "Loose belief", Kind: "belief", State: "active"},
+		{ID: "d", IndexID: 5, Title: "Dropped", Kind: "plan", State: "dropped"},
+		{ID: "e", IndexID: 6, Title: "Note", Kind: "note", State: "active"},
+	}
+
+	issues := denote.AuditPurposes(cfg, entries)
+	if len(issues) != 2 {
+		t.Fatalf("AuditPurposes: got %d issues, want 2: %+v", len(issues), issues)
+	}
+	if issues[0].IndexID != 2 || issues[1].IndexID != 3 {
+		t.Errorf("issues not ordered by index_id: %+v", issues)
+	}
+	if issues[1].Kind != denote.KindAspiration {
+		t.Errorf("kindless entry should audit as aspiration, got %q", issues[1].Kind)
+	}
+	if issues[0].Problem != denote.PurposeProblemMissing {
+		t.Errorf("Problem: got %q, want %q", issues[0].Problem, denote.PurposeProblemMissing)
+	}
+}
+
+func TestAuditPurposes_DanglingAndStale(t *testing.T) {
+	cfg := denote.DefaultKindsConfig()
+	entries := []denote.IndexEntry{
+		{ID: "p", IndexID: 1, Title: "Renamed purpose", Kind: "purpose", State: "active"},
+		{ID: "n", IndexID: 2, Title: "Not a purpose", Kind: "note", State: "active"},
+		{ID: "a", IndexID: 3, Title: "Stale", Kind: "note", State: "active", PurposeID: "p", PurposeName: "Old name"},
+		{ID: "b", IndexID: 4, Title: "Deleted purpose", Kind: "note", State: "active", PurposeID: "gone", PurposeName: "Gone"},
+		{ID: "c", IndexID: 5, Title: "Wrong kind", Kind: "note", State: "archived", PurposeID: "n", PurposeName: "Not a purpose"},
+	}
+
+	issues := denote.AuditPurposes(cfg, entries)
+	want := []string{denote.PurposeProblemStale, denote.PurposeProblemDangling, denote.PurposeProblemDangling}
+	if len(issues) != len(want) {
+		t.Fatalf("AuditPurposes: got %+v, want problems %v", issues, want)
+	}
+	for i, w := range want {
+		if issues[i].Problem != w {
+			t.Errorf("issue %d (%s): got %q, want %q", i, issues[i].Title, issues[i].Problem, w)
+		}
+	}
+	if issues[0].PurposeName != "Old name" {
+		t.Errorf("stale issue should report the stored name, got %q", issues[0].PurposeName)
+	}
+}
+
+func TestBuildPurposeTree(t *testing.T) {
+	entries := []denote.IndexEntry{
+		{ID: "life", IndexID: 1, Title: "Health", Kind: "purpose"},
+		{ID: "goal", IndexID: 2, Title: "Fitness", Kind: "purpose", PurposeID: "life"},
+		{ID: "sub", IndexID: 3, Title: "Marathon", Kind: "purpose", PurposeID: "goal"},
+		{ID: "art", IndexID: 4, Title: "Art", Kind: "purpose"},
+		{ID: "idea", IndexID: 5, Title: "Run daily", Kind: "aspiration", PurposeID: "sub"},
+		{ID: "orphan", IndexID: 6, Title: "Orphan", Kind: "purpose", PurposeID: "deleted"},
+	}
+
+	roots := denote.BuildPurposeTree(entries)
+	var titles []string
+	for _, r := range roots {
+		titles = append(titles, r.Entry.Title)
+	}
+	if len(roots) != 3 || titles[0] != "Art" || titles[1] != "Health" || titles[2] != "Orphan" {
+		t.Fatalf("roots: got %v, want [Art Health Orphan]", titles)
+	}
+	health := roots[1]
+	if len(health.Children) != 1 || health.Children[0].Entry.ID != "goal" ||
+		len(health.Children[0].Children) != 1 || health.Children[0].Children[0].Entry.ID != "sub" {
+		t.Errorf("Health subtree not nested as life -> goal -> sub")
+	}
+
+	sub := denote.PurposeSubtree(denote.PurposeParents(entries), "life")
+	for _, id := range []string{"life", "goal", "sub"} {
+		if !sub[id] {
+			t.Errorf("PurposeSubtree(life) missing %s", id)
+		}
+	}
+	if sub["art"] || sub["idea"] {
+		t.Errorf("PurposeSubtree(life) = %v, should only hold purposes beneath life", sub)
+	}
+}
+
+func TestBuildPurposeTree_BreaksCycles(t *testing.T) {
+	entries := []denote.IndexEntry{
+		{ID: "a", IndexID: 1, Title: "A", Kind: "purpose", PurposeID: "b"},
+		{ID: "b", IndexID: 2, Title: "B", Kind: "purpose", PurposeID: "a"},
+	}
+
+	roots := denote.BuildPurposeTree(entries)
+	if len(roots) != 1 || roots[0].Entry.ID != "a" {
+		t.Fatalf("expected the cycle to be cut at A, got %d roots", len(roots))
+	}
+	if len(roots[0].Children) != 1 || len(roots[0].Children[0].Children) != 0 {
+		t.Errorf("expected A -> B with no further children")
+	}
+	if sub := denote.PurposeSubtree(denote.PurposeParents(entries), "a"); len(sub) != 2 {
+		t.Errorf("PurposeSubtree on a cycle: got %v", sub)
+	}
+}
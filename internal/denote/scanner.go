@@ -1,12 +1,15 @@
 package denote
 
 import (
+	"os"
 	"path/filepath"
 
 	"github.com/mph-llm-experiments/acore"
 )
 
 // Scanner finds and loads idea files from a directory.
+// Lookups go through the on-disk index so single-idea commands only parse
+// the file they need; full scans keep the index up to date.
 type Scanner struct {
 	BaseDir string
 }
@@ -18,20 +21,122 @@ func NewScanner(dir string) *Scanner {
 
 // FindIdeas finds and parses all idea files in the directory.
 func (s *Scanner) FindIdeas() ([]*Idea, error) {
+	var ideas []*Idea
+	err := s.EachIdea(func(idea *Idea) bool {
+		ideas = append(ideas, idea)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ideas, nil
+}
+
+// EachIdea parses idea files one at a time, calling fn with each as soon as
+// it is read, until fn returns false. Files that fail to parse are skipped.
+func (s *Scanner) EachIdea(fn func(*Idea) bool) error {
 	sc := &acore.Scanner{Store: acore.NewLocalStore(s.BaseDir)}
 	names, err := sc.FindByType(TypeIdea)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var ideas []*Idea
+	ix := loadIndex(s.BaseDir)
+	seen := make(map[string]bool, len(names))
+
+	complete := true
 	for _, name := range names {
-		idea, err := ParseIdeaFile(filepath.Join(s.BaseDir, name))
+		path := filepath.Join(s.BaseDir, name)
+		idea, err := ParseIdeaFile(path)
 		if err != nil {
 			continue
 		}
-		ideas = append(ideas, idea)
+		if info, err := os.Stat(path); err == nil {
+			ix.put(idea, info)
+			seen[name] = true
+		}
+		if !fn(idea) {
+			complete = false
+			break
+		}
 	}
 
-	return ideas, nil
+	// Only a full pass shows which files are gone
+	if complete {
+		for name := range ix.Entries {
+			if !seen[name] {
+				ix.remove(name)
+			}
+		}
+	}
+	// The index is a cache; failing to write it must not fail the scan.
+	_ = ix.save(s.BaseDir)
+
+	return nil
+}
+
+// FindByIndexID returns the idea with the given index_id, or nil if none exists.
+func (s *Scanner) FindByIndexID(id int) (*Idea, error) {
+	return s.lookup(func(ix *ideaIndex) *IndexEntry { return ix.byIndexID[id] })
+}
+
+// FindByEntityID returns the idea with the given ULID (or legacy Denote ID),
+// or nil if none exists.
+func (s *Scanner) FindByEntityID(id string) (*Idea, error) {
+	return s.lookup(func(ix *ideaIndex) *IndexEntry { return ix.byID[id] })
+}
+
+// Entries returns the index entries for every idea in the directory,
+// refreshing only files that changed since the index was last written.
+func (s *Scanner) Entries() ([]IndexEntry, error) {
+	ix := loadIndex(s.BaseDir)
+	if err := ix.refresh(s.BaseDir); err != nil {
+		return nil, err
+	}
+	_ = ix.save(s.BaseDir)
+
+	entries := make([]IndexEntry, 0, len(ix.Entries))
+	for _, e := range ix.Entries {
+		entries = append(entries, *e)
+	}
+	return entries, nil
+}
+
+// PurposeIssues reports missing, dangling and stale purpose references
+// across the directory. See AuditPurposes.
+func (s *Scanner) PurposeIssues(kc *KindsConfig) ([]PurposeIssue, error) {
+	entries, err := s.Entries()
+	if err != nil {
+		return nil, err
+	}
+	return AuditPurposes(kc, entries), nil
+}
+
+// lookup resolves an idea through the index. If the indexed file is missing,
+// changed, or no longer holds the expected idea, the index is refreshed and
+// the lookup retried once.
+func (s *Scanner) lookup(find func(*ideaIndex) *IndexEntry) (*Idea, error) {
+	ix := loadIndex(s.BaseDir)
+	defer func() { _ = ix.save(s.BaseDir) }()
+
+	if e := find(ix); e != nil {
+		path := filepath.Join(s.BaseDir, e.File)
+		if info, err := os.Stat(path); err == nil {
+			if idea, err := ParseIdeaFile(path); err == nil {
+				ix.put(idea, info)
+				if find(ix) == ix.Entries[e.File] {
+					return idea, nil
+				}
+			}
+		}
+	}
+
+	if err := ix.refresh(s.BaseDir); err != nil {
+		return nil, err
+	}
+	e := find(ix)
+	if e == nil {
+		return nil, nil
+	}
+	return ParseIdeaFile(filepath.Join(s.BaseDir, e.File))
 }
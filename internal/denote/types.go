@@ -79,40 +79,6 @@ func IsValidMaturity(maturity string) bool {
 	return false
 }
 
-// allowedTransitions defines which state transitions are valid per the spec.
-var allowedTransitions = map[string][]string{
-	StateSeed:      {StateDraft},
-	StateDraft:     {StateActive},
-	StateActive:    {StateIterating, StateImplemented, StateArchived, StateRejected, StateDropped},
-	StateIterating: {StateActive, StateImplemented, StateArchived, StateRejected, StateDropped},
-	StateArchived:  {StateActive},
-	// Terminal states with no outbound transitions:
-	// StateImplemented, StateRejected, StateDropped
-}
-
-// ValidateStateTransition checks if a state transition is allowed.
-func ValidateStateTransition(from, to string) error {
-	if !IsValidState(from) {
-		return fmt.Errorf("invalid source state: %s", from)
-	}
-	if !IsValidState(to) {
-		return fmt.Errorf("invalid target state: %s", to)
-	}
-
-	allowed, ok := allowedTransitions[from]
-	if !ok {
-		return fmt.Errorf("no transitions allowed from terminal state %q", from)
-	}
-
-	for _, s := range allowed {
-		if s == to {
-			return nil
-		}
-	}
-
-	return fmt.Errorf("transition from %q to %q is not allowed", from, to)
-}
-
 // IsValidKind checks if a kind value is valid.
 func IsValidKind(kind string) bool {
 	switch kind {
@@ -128,46 +94,19 @@ func IsSimpleKind(kind string) bool {
 	return kind == KindNote || kind == KindFact || kind == KindPurpose
 }
 
-// displayLabels maps canonical states to kind-specific display labels.
-// Only states that differ between kinds are listed.
-var displayLabels = map[string]map[string]string{
-	KindBelief: {
-		StateActive:      "considering",
-		StateIterating:   "reconsidering",
-		StateImplemented: "accepted",
-	},
-	KindPlan: {
-		StateActive:      "committed",
-		StateIterating:   "replanning",
-		StateImplemented: "completed",
-	},
-}
-
-// DisplayState returns the kind-specific display label for a canonical state.
+// DisplayState returns the kind-specific display label for a canonical state,
+// using the built-in kinds. Callers with a loaded kinds.json should use
+// KindsConfig.DisplayState instead.
 func DisplayState(canonical, kind string) string {
-	if kindMap, ok := displayLabels[kind]; ok {
-		if label, ok := kindMap[canonical]; ok {
-			return label
-		}
-	}
-	return canonical
+	return builtinKinds.DisplayState(canonical, kind)
 }
 
 // ResolveDisplayState converts a display label (possibly kind-specific) back
-// to the canonical state value. Returns the canonical state and the kind it
-// belongs to (empty string if the label is shared or already canonical).
+// to the canonical state value using the built-in kinds. Returns the canonical
+// state and the kind it belongs to (empty string if the label is shared or
+// already canonical).
 func ResolveDisplayState(display string) (canonical string, matchedKind string) {
-	if IsValidState(display) {
-		return display, ""
-	}
-	for kind, kindMap := range displayLabels {
-		for canonical, label := range kindMap {
-			if label == display {
-				return canonical, kind
-			}
-		}
-	}
-	return display, ""
+	return builtinKinds.ResolveDisplayState(display)
 }
 
 // ValidateIdea checks business rules for an idea.
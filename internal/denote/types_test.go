@@ -36,49 +36,6 @@ func TestIsValidMaturity(t *testing.T) {
 	}
 }
 
-func TestValidateStateTransition(t *testing.T) {
-	tests := []struct {
-		from    string
-		to      string
-		wantErr bool
-	}{
-		// Valid transitions
-		{"seed", "draft", false},
-		{"draft", "active", false},
-		{"active", "iterating", false},
-		{"active", "implemented", false},
-		{"active", "archived", false},
-		{"active", "rejected", false},
-		{"active", "dropped", false},
-		{"iterating", "active", false},
-		{"iterating", "implemented", false},
-		{"iterating", "archived", false},
-		{"iterating", "rejected", false},
-		{"iterating", "dropped", false},
-		{"archived", "active", false},
-
-		// Invalid transitions
-		{"seed", "active", true},      // must go through draft
-		{"seed", "implemented", true},  // can't skip ahead
-		{"draft", "iterating", true},   // must go through active
-		{"draft", "seed", true},        // can't go backward
-		{"implemented", "active", true}, // terminal state
-		{"rejected", "active", true},    // terminal state
-		{"dropped", "active", true},     // terminal state
-
-		// Invalid state values
-		{"bogus", "active", true},
-		{"active", "bogus", true},
-	}
-
-	for _, tt := range tests {
-		err := ValidateStateTransition(tt.from, tt.to)
-		if (err != nil) != tt.wantErr {
-			t.Errorf("ValidateStateTransition(%q, %q): got err=%v, wantErr=%v", tt.from, tt.to, err, tt.wantErr)
-		}
-	}
-}
-
 func TestValidateIdea(t *testing.T) {
 	// Valid: rejected with reason
 	i := &Idea{}
@@ -0,0 +1,161 @@
+package denote
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/mph-llm-experiments/acore"
+)
+
+// Watch event types.
+const (
+	EventCreated      = "created"
+	EventModified     = "modified"
+	EventDeleted      = "deleted"
+	EventStateChanged = "state_changed"
+)
+
+// WatchEvent is a change to one idea between two polls. Before is nil for
+// created ideas and After for deleted ones. Changed lists the frontmatter
+// keys that differ, plus "content" when the body changed. A change of state
+// is reported as EventStateChanged rather than EventModified.
+type WatchEvent struct {
+	Type    string   `json:"type"`
+	Time    string   `json:"time"`
+	File    string   `json:"file"`
+	Changed []string `json:"changed,omitempty"`
+	Before  *Idea    `json:"before,omitempty"`
+	After   *Idea    `json:"after,omitempty"`
+}
+
+// watchedFile is an idea file as last seen by a Watcher.
+type watchedFile struct {
+	modTime int64
+	size    int64
+	idea    *Idea
+}
+
+// Watcher reports changes to the idea files in a directory by polling.
+// Files are only re-parsed when their size or modification time changes;
+// ideas are matched by ID, so a renamed file is a modification, not a
+// delete and a create.
+type Watcher struct {
+	dir   string
+	files map[string]watchedFile
+}
+
+// NewWatcher returns a watcher whose first Poll reports changes made after
+// this call.
+func NewWatcher(dir string) (*Watcher, error) {
+	w := &Watcher{dir: dir, files: map[string]watchedFile{}}
+	if _, err := w.Poll(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Poll re-reads the directory and returns the changes since the last poll,
+// ordered by file name. A file that fails to parse, e.g. one caught
+// mid-write, keeps its previous version until it parses again.
+func (w *Watcher) Poll() ([]WatchEvent, error) {
+	sc := &acore.Scanner{Store: acore.NewLocalStore(w.dir)}
+	names, err := sc.FindByType(TypeIdea)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]watchedFile, len(names))
+	for _, name := range names {
+		path := filepath.Join(w.dir, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		old, known := w.files[name]
+		if known && old.modTime == info.ModTime().UnixNano() && old.size == info.Size() {
+			files[name] = old
+			continue
+		}
+		idea, err := ParseIdeaFile(path)
+		if err != nil {
+			if known {
+				files[name] = old
+			}
+			continue
+		}
+		files[name] = watchedFile{modTime: info.ModTime().UnixNano(), size: info.Size(), idea: idea}
+	}
+
+	before := ideasByID(w.files)
+	after := ideasByID(files)
+	w.files = files
+
+	now := time.Now().Format(time.RFC3339)
+	var events []WatchEvent
+	for id, a := range after {
+		b, ok := before[id]
+		if !ok {
+			events = append(events, WatchEvent{Type: EventCreated, Time: now, File: a.FilePath, After: a})
+			continue
+		}
+		if a == b {
+			continue
+		}
+		changed := changedFields(b, a)
+		if len(changed) == 0 {
+			continue
+		}
+		typ := EventModified
+		if a.State != b.State {
+			typ = EventStateChanged
+		}
+		events = append(events, WatchEvent{Type: typ, Time: now, File: a.FilePath, Changed: changed, Before: b, After: a})
+	}
+	for id, b := range before {
+		if _, ok := after[id]; !ok {
+			events = append(events, WatchEvent{Type: EventDeleted, Time: now, File: b.FilePath, Before: b})
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].File < events[j].File })
+	return events, nil
+}
+
+func ideasByID(files map[string]watchedFile) map[string]*Idea {
+	byID := make(map[string]*Idea, len(files))
+	for _, f := range files {
+		byID[f.idea.ID] = f.idea
+	}
+	return byID
+}
+
+// changedFields returns the sorted JSON keys whose values differ between
+// a and b, plus "content" for a body change.
+func changedFields(a, b *Idea) []string {
+	var am, bm map[string]any
+	aj, _ := json.Marshal(a)
+	bj, _ := json.Marshal(b)
+	_ = json.Unmarshal(aj, &am)
+	_ = json.Unmarshal(bj, &bm)
+
+	var changed []string
+	for k, v := range bm {
+		if !reflect.DeepEqual(am[k], v) {
+			changed = append(changed, k)
+		}
+	}
+	for k := range am {
+		if _, ok := bm[k]; !ok {
+			changed = append(changed, k)
+		}
+	}
+	if a.Content != b.Content {
+		changed = append(changed, "content")
+	}
+	sort.Strings(changed)
+	return changed
+}
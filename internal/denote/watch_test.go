@@ -0,0 +1,70 @@
+package denote
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rewrite replaces old with new in the file at path and moves its mtime on.
+func rewrite(t *testing.T, path, old, new string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(string(data), old, new, 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcher_Poll(t *testing.T) {
+	dir := t.TempDir()
+	first := writeTestIdea(t, dir, "01TESTID0000000000000000A1", 1, "First")
+	second := writeTestIdea(t, dir, "01TESTID0000000000000000A2", 2, "Second")
+
+	w, err := NewWatcher(dir)
+	if err != nil {
+		t.Fatalf("NewWatcher: %v", err)
+	}
+	if events, _ := w.Poll(); len(events) != 0 {
+		t.Fatalf("no changes: got %+v", events)
+	}
+
+	rewrite(t, first, "state: seed", "state: draft")
+	rewrite(t, second, "title: Second", "title: Second, renamed")
+	writeTestIdea(t, dir, "01TESTID0000000000000000A3", 3, "Third")
+
+	events, err := w.Poll()
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3: %+v", len(events), events)
+	}
+	if e := events[0]; e.Type != EventStateChanged || e.Before.State != StateSeed || e.After.State != StateDraft {
+		t.Errorf("state change: got %+v", e)
+	}
+	if e := events[1]; e.Type != EventModified || strings.Join(e.Changed, ",") != "title" {
+		t.Errorf("modified: got %s %v", e.Type, e.Changed)
+	}
+	if e := events[2]; e.Type != EventCreated || e.Before != nil || e.After.Title != "Third" {
+		t.Errorf("created: got %+v", e)
+	}
+
+	if err := os.Remove(second); err != nil {
+		t.Fatal(err)
+	}
+	events, err = w.Poll()
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != EventDeleted || events[0].Before.IndexID != 2 {
+		t.Errorf("deleted: got %+v", events)
+	}
+}
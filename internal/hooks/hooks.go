@@ -0,0 +1,126 @@
+// Package hooks runs user commands at points in an idea's lifecycle.
+//
+// Hooks are shell commands configured per event in config.toml:
+//
+//	[hooks]
+//	pre-create = ["~/bin/check-title.sh"]
+//	post-state-change = ["~/bin/open-project.sh", "make -C ~/dash"]
+//
+// Each command runs with sh -c in the ideas directory and receives a
+// Payload as JSON on stdin. A pre- hook that exits non-zero aborts the
+// operation; a failing post- hook is reported but the change stands.
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/mph-llm-experiments/anote/internal/denote"
+)
+
+// Lifecycle events.
+const (
+	PreCreate       = "pre-create"
+	PostCreate      = "post-create"
+	PreStateChange  = "pre-state-change"
+	PostStateChange = "post-state-change"
+	PostLog         = "post-log"
+	PreDelete       = "pre-delete"
+)
+
+// Events lists every lifecycle event, in the order they are documented.
+var Events = []string{PreCreate, PostCreate, PreStateChange, PostStateChange, PostLog, PreDelete}
+
+// Payload is the JSON a hook receives on stdin. OldState is empty for
+// pre-create and post-create, NewState for pre-delete; Message is set for
+// post-log. The idea of a pre-create hook has no index_id yet.
+type Payload struct {
+	Event    string       `json:"event"`
+	Idea     *denote.Idea `json:"idea"`
+	OldState string       `json:"old_state,omitempty"`
+	NewState string       `json:"new_state,omitempty"`
+	Message  string       `json:"message,omitempty"`
+}
+
+// Runner runs the commands configured for each event. A nil Runner runs
+// nothing.
+type Runner struct {
+	dir   string
+	hooks map[string][]string
+}
+
+// New returns a runner for the hooks configured for the ideas in dir.
+func New(dir string, hooks map[string][]string) (*Runner, error) {
+	for event := range hooks {
+		if !isEvent(event) {
+			return nil, fmt.Errorf("unknown hook event %q: use %s", event, strings.Join(Events, ", "))
+		}
+	}
+	return &Runner{dir: dir, hooks: hooks}, nil
+}
+
+func isEvent(event string) bool {
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Has reports whether any command is configured for event.
+func (r *Runner) Has(event string) bool {
+	return r != nil && len(r.hooks[event]) > 0
+}
+
+// Run runs the commands for event in order, passing p on stdin. For a pre-
+// event the first failure stops the run and is returned so the caller can
+// abort. For a post- event every command runs and the failures are
+// returned together.
+func (r *Runner) Run(event string, p Payload) error {
+	if !r.Has(event) {
+		return nil
+	}
+	p.Event = event
+	input, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s hook input: %w", event, err)
+	}
+
+	pre := strings.HasPrefix(event, "pre-")
+	var errs []error
+	for _, command := range r.hooks[event] {
+		if err := r.exec(event, command, p.Idea, input); err != nil {
+			if pre {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Runner) exec(event, command string, i *denote.Idea, input []byte) error {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = r.dir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(), "ANOTE_EVENT="+event, "ANOTE_DIR="+r.dir)
+	if i != nil {
+		cmd.Env = append(cmd.Env, "ANOTE_ID="+i.ID, "ANOTE_INDEX_ID="+strconv.Itoa(i.IndexID))
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s hook %q failed: %w: %s", event, command, err, msg)
+		}
+		return fmt.Errorf("%s hook %q failed: %w", event, command, err)
+	}
+	return nil
+}
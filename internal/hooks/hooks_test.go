@@ -0,0 +1,82 @@
+package hooks
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mph-llm-experiments/anote/internal/denote"
+)
+
+func TestNew_UnknownEvent(t *testing.T) {
+	if _, err := New(t.TempDir(), map[string][]string{"post-update": {"true"}}); err == nil {
+		t.Error("expected error for unknown event")
+	}
+}
+
+func TestRun_Payload(t *testing.T) {
+	dir := t.TempDir()
+	r, err := New(dir, map[string][]string{
+		PostStateChange: {`cat > payload.json; echo "$ANOTE_EVENT $ANOTE_INDEX_ID" > env.txt`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i := &denote.Idea{}
+	i.ID = "01ABC"
+	i.IndexID = 7
+	i.Title = "Mentoring guide"
+	if err := r.Run(PostStateChange, Payload{Idea: i, OldState: "seed", NewState: "active"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "payload.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Payload
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("payload: %v (%s)", err, data)
+	}
+	if got.Event != PostStateChange || got.OldState != "seed" || got.NewState != "active" || got.Idea.Title != "Mentoring guide" {
+		t.Errorf("payload: got %+v", got)
+	}
+	env, _ := os.ReadFile(filepath.Join(dir, "env.txt"))
+	if strings.TrimSpace(string(env)) != "post-state-change 7" {
+		t.Errorf("env: got %q", env)
+	}
+}
+
+func TestRun_Failures(t *testing.T) {
+	dir := t.TempDir()
+	r, err := New(dir, map[string][]string{
+		PreDelete: {"echo keep it >&2; exit 3", "touch ran"},
+		PostLog:   {"exit 1", "touch ran"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.Run(PreDelete, Payload{})
+	if err == nil || !strings.Contains(err.Error(), "keep it") {
+		t.Errorf("pre-delete: got %v, want failure with hook output", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ran")); err == nil {
+		t.Error("pre- hooks should stop at the first failure")
+	}
+
+	if err := r.Run(PostLog, Payload{}); err == nil {
+		t.Error("post-log: expected failure")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ran")); err != nil {
+		t.Error("post- hooks should all run despite a failure")
+	}
+
+	var none *Runner
+	if err := none.Run(PreCreate, Payload{}); err != nil {
+		t.Errorf("nil runner: %v", err)
+	}
+}
@@ -0,0 +1,748 @@
+package idea
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mph-llm-experiments/acore"
+	"github.com/mph-llm-experiments/anote/internal/config"
+	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/hooks"
+)
+
+// BatchOps lists the operations a batch may contain.
+var BatchOps = []string{"new", "update", "tag", "link", "log", "reject"}
+
+// BatchOp is one operation of a batch. Op selects the operation and which
+// other fields apply:
+//
+//   - new: title, kind, tags, body, template, purpose; ref names the new idea so later
+//     operations can refer to it as "$ref"
+//   - update: id and any of title, body, state, kind, maturity, purpose (or
+//     none) and plan_for (or none); force skips the transition check
+//   - tag: id, tag, remove
+//   - link: id, target (linked both ways)
+//   - log: id, message
+//   - reject: id, reason, force
+//
+// id, target and purpose take an index_id, a ULID or a "$ref"; purpose also
+// takes a purpose title.
+type BatchOp struct {
+	Op       string   `json:"op"`
+	Ref      string   `json:"ref,omitempty"`
+	ID       string   `json:"id,omitempty"`
+	Title    string   `json:"title,omitempty"`
+	Kind     string   `json:"kind,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Body     string   `json:"body,omitempty"`
+	Template string   `json:"template,omitempty"`
+	Purpose  string   `json:"purpose,omitempty"`
+	State    string   `json:"state,omitempty"`
+	Maturity string   `json:"maturity,omitempty"`
+	PlanFor  string   `json:"plan_for,omitempty"`
+	Tag      string   `json:"tag,omitempty"`
+	Remove   bool     `json:"remove,omitempty"`
+	Target   string   `json:"target,omitempty"`
+	Message  string   `json:"message,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+	Force    bool     `json:"force,omitempty"`
+}
+
+// BatchOptions configures RunBatch. Rules and Hooks are the automation
+// from config; without them a batch applies no rules and runs no hooks.
+type BatchOptions struct {
+	DryRun bool
+	Rules  []config.Rule
+	Hooks  *hooks.Runner
+}
+
+// BatchResult reports the outcome of one operation, in the order given.
+// Warning reports post- hooks of the operation that failed after the batch
+// was applied.
+type BatchResult struct {
+	Op      string `json:"op"`
+	Ref     string `json:"ref,omitempty"`
+	OK      bool   `json:"ok"`
+	ID      string `json:"id,omitempty"`
+	IndexID int    `json:"index_id,omitempty"`
+	Title   string `json:"title,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Warning string `json:"warning,omitempty"`
+
+	target *batchIdea
+	post   []postHook
+}
+
+// postHook is a post- hook an operation runs once the batch is written.
+type postHook struct {
+	event   string
+	payload hooks.Payload
+}
+
+// ParseBatch reads operations as a JSON array or as one JSON object per
+// line. Unknown fields are an error so that typos do not pass silently.
+func ParseBatch(r io.Reader) ([]BatchOp, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read operations: %w", err)
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no operations given")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var ops []BatchOp
+	if data[0] == '[' {
+		if err := dec.Decode(&ops); err != nil {
+			return nil, fmt.Errorf("invalid operations: %w", err)
+		}
+		if dec.More() {
+			return nil, fmt.Errorf("invalid operations: unexpected data after the array")
+		}
+	} else {
+		for n := 1; ; n++ {
+			var op BatchOp
+			if err := dec.Decode(&op); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("operation %d: %w", n, err)
+			}
+			ops = append(ops, op)
+		}
+	}
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("no operations given")
+	}
+	return ops, nil
+}
+
+// RunBatch validates every operation against the ideas in dir before
+// writing anything. If all are valid it writes the changed and new ideas,
+// restoring the originals and removing new files if a write fails. The
+// results line up with ops and report whether each one validated; a non-nil
+// error means nothing was applied. With DryRun nothing is written and new
+// ideas have no index_id.
+//
+// Rules apply to each idea an update, tag or reject changes, as Mutator.Save
+// does. The pre-create and pre-state-change hooks run during validation, so
+// one that fails fails its operation and with it the batch; the post- hooks
+// run once the batch is written.
+//
+// Index IDs taken by new ideas are not handed back on rollback.
+func RunBatch(dir string, ops []BatchOp, opts BatchOptions) ([]BatchResult, error) {
+	b, err := loadBatch(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]BatchResult, len(ops))
+	failed := 0
+	for n, op := range ops {
+		r := &results[n]
+		r.Op, r.Ref = op.Op, strings.TrimPrefix(op.Ref, "$")
+		target, err := b.apply(op)
+		post := b.post
+		b.post = nil
+		if err != nil {
+			r.Error = err.Error()
+			failed++
+			if op.Op == "new" && r.Ref != "" {
+				b.failedRefs[r.Ref] = true
+			}
+			continue
+		}
+		r.OK = true
+		r.target = target
+		r.post = post
+	}
+	if failed > 0 {
+		return fillResults(results), fmt.Errorf("%d of %d operation(s) failed validation: nothing was applied", failed, len(ops))
+	}
+
+	if !opts.DryRun {
+		if err := b.commit(); err != nil {
+			return fillResults(results), err
+		}
+		for n := range results {
+			var errs []error
+			for _, h := range results[n].post {
+				if err := b.hooks.Run(h.event, h.payload); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			if err := errors.Join(errs...); err != nil {
+				results[n].Warning = err.Error()
+			}
+		}
+	}
+	return fillResults(results), nil
+}
+
+// fillResults copies the identity of each operation's idea into its result.
+func fillResults(results []BatchResult) []BatchResult {
+	for n := range results {
+		if t := results[n].target; t != nil {
+			results[n].ID = t.idea.ID
+			results[n].IndexID = t.idea.IndexID
+			results[n].Title = t.idea.Title
+		}
+	}
+	return results
+}
+
+// batchIdea is an idea as staged by a batch.
+type batchIdea struct {
+	idea     *denote.Idea
+	body     string
+	modified string // as read, before the batch
+	isNew    bool
+	dirty    bool
+}
+
+// batch is the working set of ideas a batch validates against and writes.
+type batch struct {
+	dir        string
+	kinds      *denote.KindsConfig
+	ideas      []*batchIdea
+	byID       map[string]*batchIdea
+	byIndex    map[int]*batchIdea
+	refs       map[string]*batchIdea
+	failedRefs map[string]bool
+	now        time.Time
+
+	mut   *Mutator
+	hooks *hooks.Runner
+	post  []postHook // queued by the operation being applied
+}
+
+func loadBatch(dir string, opts BatchOptions) (*batch, error) {
+	kinds, err := denote.LoadKindsConfig(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kinds config: %w", err)
+	}
+	mut, err := NewMutator(dir, kinds, opts.Rules, opts.Hooks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules config: %w", err)
+	}
+	ideas, err := denote.NewScanner(dir).FindIdeas()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan ideas: %w", err)
+	}
+	b := &batch{
+		dir:        dir,
+		kinds:      kinds,
+		byID:       make(map[string]*batchIdea),
+		byIndex:    make(map[int]*batchIdea),
+		refs:       make(map[string]*batchIdea),
+		failedRefs: make(map[string]bool),
+		now:        time.Now(),
+		mut:        mut,
+		hooks:      opts.Hooks,
+	}
+	for _, i := range ideas {
+		b.add(&batchIdea{idea: i, body: ExtractContent(i.Content), modified: i.Modified})
+	}
+	return b, nil
+}
+
+func (b *batch) add(bi *batchIdea) {
+	b.ideas = append(b.ideas, bi)
+	b.byID[bi.idea.ID] = bi
+	if bi.idea.IndexID > 0 {
+		b.byIndex[bi.idea.IndexID] = bi
+	}
+}
+
+// touch marks bi as changed and bumps its modified time.
+func (b *batch) touch(bi *batchIdea) {
+	bi.dirty = true
+	bi.idea.Modified = nextModified(bi.modified, b.now)
+}
+
+// resolve finds an idea by "$ref", index_id or ULID.
+func (b *batch) resolve(ref string) (*batchIdea, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, fmt.Errorf("idea ID required")
+	}
+	if name, ok := strings.CutPrefix(ref, "$"); ok {
+		if bi := b.refs[name]; bi != nil {
+			return bi, nil
+		}
+		if b.failedRefs[name] {
+			return nil, fmt.Errorf("%s: the operation creating it failed", ref)
+		}
+		return nil, fmt.Errorf("%s: no earlier new operation has that ref", ref)
+	}
+	if n, err := strconv.Atoi(ref); err == nil {
+		if bi := b.byIndex[n]; bi != nil {
+			return bi, nil
+		}
+		return nil, fmt.Errorf("idea %d not found", n)
+	}
+	if bi := b.byID[ref]; bi != nil {
+		return bi, nil
+	}
+	return nil, fmt.Errorf("idea with ID %s not found", ref)
+}
+
+// resolvePurpose finds a purpose-kind idea as resolve does, or by title.
+func (b *batch) resolvePurpose(ref string) (*batchIdea, error) {
+	bi, err := b.resolve(ref)
+	if err != nil && !strings.HasPrefix(ref, "$") {
+		var matches []*batchIdea
+		for _, c := range b.ideas {
+			if c.idea.Kind == denote.KindPurpose && strings.EqualFold(c.idea.Title, ref) {
+				matches = append(matches, c)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("purpose %q not found", ref)
+		case 1:
+			bi, err = matches[0], nil
+		default:
+			return nil, fmt.Errorf("purpose title %q is ambiguous: use its index_id or ULID", ref)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bi.idea.Kind != denote.KindPurpose {
+		return nil, fmt.Errorf("idea %q is not a purpose (kind: %s)", bi.idea.Title, kindOrDefault(bi.idea.Kind))
+	}
+	return bi, nil
+}
+
+// entries returns index entries for the working set, for purpose checks.
+func (b *batch) entries() []denote.IndexEntry {
+	entries := make([]denote.IndexEntry, 0, len(b.ideas))
+	for _, bi := range b.ideas {
+		i := bi.idea
+		entries = append(entries, denote.IndexEntry{ID: i.ID, IndexID: i.IndexID, Title: i.Title, Kind: i.Kind, State: i.State, PurposeID: i.PurposeID})
+	}
+	return entries
+}
+
+// apply validates op and stages its changes, returning the idea it acted on.
+func (b *batch) apply(op BatchOp) (*batchIdea, error) {
+	switch op.Op {
+	case "new":
+		return b.applyNew(op)
+	case "update", "tag", "reject":
+		bi, err := b.resolve(op.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := b.applyChange(bi, op); err != nil {
+			return nil, err
+		}
+		return bi, nil
+	case "link":
+		return b.applyLink(op)
+	case "log":
+		return b.applyLog(op)
+	case "":
+		return nil, fmt.Errorf("op required: use %s", strings.Join(BatchOps, ", "))
+	}
+	return nil, fmt.Errorf("unknown op %q: use %s", op.Op, strings.Join(BatchOps, ", "))
+}
+
+// applyChange stages an update, tag or reject of bi and applies the rules it
+// triggers. A changed state must pass the pre-state-change hook. If anything
+// fails, bi is left as it was.
+func (b *batch) applyChange(bi *batchIdea, op BatchOp) error {
+	before := *bi.idea
+	before.Tags = append([]string(nil), bi.idea.Tags...)
+	body := bi.body
+
+	err := b.stageChange(bi, op)
+	if err == nil {
+		err = b.applyRules(bi, &before)
+	}
+	stateChange := hooks.Payload{Idea: bi.idea, OldState: before.State, NewState: bi.idea.State}
+	if err == nil && stateChange.OldState != stateChange.NewState {
+		if err = b.hooks.Run(hooks.PreStateChange, stateChange); err == nil {
+			b.post = append(b.post, postHook{hooks.PostStateChange, stateChange})
+		}
+	}
+	if err != nil {
+		*bi.idea = before
+		bi.body = body
+		return err
+	}
+	return nil
+}
+
+func (b *batch) stageChange(bi *batchIdea, op BatchOp) error {
+	switch op.Op {
+	case "update":
+		return b.applyUpdate(bi, op)
+	case "tag":
+		return b.applyTag(bi, op)
+	}
+	return b.applyReject(bi, op)
+}
+
+// applyRules applies the rules triggered by the change to bi since before
+// and logs each one that fired.
+func (b *batch) applyRules(bi *batchIdea, before *denote.Idea) error {
+	c := &Change{Idea: bi.idea, Before: before}
+	if err := b.mut.ApplyRules(c); err != nil {
+		return err
+	}
+	if len(c.Fired) == 0 {
+		return nil
+	}
+	i := bi.idea
+	kind := kindOrDefault(i.Kind)
+	if i.PurposeID == "" && b.kinds.PurposeRequiredFor(kind, i.State) {
+		return fmt.Errorf("%s ideas require a purpose to be %s: give purpose",
+			kind, b.kinds.DisplayState(i.State, kind))
+	}
+	// Entries go to the top of the log, so add the last rule first
+	for n := len(c.Fired) - 1; n >= 0; n-- {
+		bi.body = AddLogEntry(bi.body, c.Fired[n])
+	}
+	b.touch(bi)
+	return nil
+}
+
+func (b *batch) applyNew(op BatchOp) (*batchIdea, error) {
+	title := strings.TrimSpace(op.Title)
+	if title == "" {
+		return nil, fmt.Errorf("title required")
+	}
+	ref := strings.TrimPrefix(op.Ref, "$")
+	if ref != "" && (b.refs[ref] != nil || b.failedRefs[ref]) {
+		return nil, fmt.Errorf("ref %q is already used in this batch", ref)
+	}
+
+	kind := op.Kind
+	if kind == "" {
+		kind = denote.KindAspiration
+	}
+	if !b.kinds.KindExists(kind) {
+		return nil, fmt.Errorf("invalid kind %q: use %s", kind, strings.Join(b.kinds.AllKinds(), ", "))
+	}
+
+	n := NewIdea{Title: title, Kind: kind, Body: op.Body, Template: op.Template}
+	for _, t := range op.Tags {
+		if t = strings.TrimSpace(t); t != "" {
+			n.Tags = append(n.Tags, t)
+		}
+	}
+	if op.Purpose != "" {
+		p, err := b.resolvePurpose(op.Purpose)
+		if err != nil {
+			return nil, err
+		}
+		n.Purpose = p.idea
+	}
+	if n.Purpose == nil && b.kinds.PurposeRequired(kind) && b.kinds.FailOnMissingPurpose() {
+		return nil, fmt.Errorf("%s ideas require a purpose", kind)
+	}
+
+	i, content, err := buildIdea(b.dir, b.kinds, kind, n)
+	if err != nil {
+		return nil, err
+	}
+	created := hooks.Payload{Idea: i, NewState: i.State}
+	if err := b.hooks.Run(hooks.PreCreate, created); err != nil {
+		return nil, err
+	}
+	b.post = append(b.post, postHook{hooks.PostCreate, created})
+	bi := &batchIdea{idea: i, body: content, isNew: true, dirty: true}
+	b.add(bi)
+	if ref != "" {
+		b.refs[ref] = bi
+	}
+	return bi, nil
+}
+
+func (b *batch) applyUpdate(bi *batchIdea, op BatchOp) error {
+	if op.Title == "" && op.Body == "" && op.State == "" && op.Kind == "" && op.Maturity == "" && op.Purpose == "" && op.PlanFor == "" {
+		return fmt.Errorf("nothing to update: give title, body, state, kind, maturity, purpose or plan_for")
+	}
+	i := bi.idea
+
+	if op.Kind != "" && !b.kinds.KindExists(op.Kind) {
+		return fmt.Errorf("invalid kind %q: use %s", op.Kind, strings.Join(b.kinds.AllKinds(), ", "))
+	}
+	effectiveKind := kindOrDefault(i.Kind)
+	if op.Kind != "" {
+		effectiveKind = op.Kind
+	}
+
+	// Validate everything before touching the staged idea, so a failed
+	// update leaves it as it was for the operations after it.
+	state := ""
+	if op.State != "" {
+		state, _ = b.kinds.ResolveDisplayState(op.State)
+		if state == denote.StateRejected {
+			return fmt.Errorf("use a reject operation to reject an idea")
+		}
+		if !b.kinds.IsCompliant(effectiveKind, state) {
+			return fmt.Errorf("invalid state %q for kind %s: use %s",
+				state, effectiveKind, strings.Join(b.kinds.ValidStatesFor(effectiveKind), ", "))
+		}
+		if !op.Force {
+			if err := b.kinds.ValidateTransition(effectiveKind, i.State, state); err != nil {
+				return fmt.Errorf("%w (set force to override)", err)
+			}
+		}
+	}
+	if op.Kind != "" && state == "" && !b.kinds.IsCompliant(op.Kind, i.State) {
+		return fmt.Errorf("state %q is not valid for kind %s: also give state (%s)",
+			i.State, op.Kind, strings.Join(b.kinds.ValidStatesFor(op.Kind), ", "))
+	}
+	if op.Maturity != "" {
+		if !b.kinds.UsesMaturity(effectiveKind) {
+			return fmt.Errorf("%s ideas do not use maturity", effectiveKind)
+		}
+		if !denote.IsValidMaturity(op.Maturity) {
+			return fmt.Errorf("invalid maturity %q: use crawl, walk, or run", op.Maturity)
+		}
+	}
+	plannedFor := i.PlannedFor
+	if op.PlanFor != "" {
+		plannedFor = ""
+		if strings.ToLower(op.PlanFor) != "none" {
+			parsed, err := acore.ParseNaturalDate(op.PlanFor)
+			if err != nil {
+				return fmt.Errorf("invalid plan_for date: %v", err)
+			}
+			plannedFor = parsed
+		}
+	}
+	purposeID, purposeName := i.PurposeID, i.PurposeName
+	if op.Purpose != "" {
+		purposeID, purposeName = "", ""
+		if strings.ToLower(op.Purpose) != "none" {
+			p, err := b.resolvePurpose(op.Purpose)
+			if err != nil {
+				return err
+			}
+			if p == bi {
+				return fmt.Errorf("a purpose cannot be attached to itself")
+			}
+			if effectiveKind == denote.KindPurpose {
+				if denote.PurposeSubtree(denote.PurposeParents(b.entries()), i.ID)[p.idea.ID] {
+					return fmt.Errorf("purpose %q is beneath %q: attaching would create a cycle", p.idea.Title, i.Title)
+				}
+			}
+			purposeID, purposeName = p.idea.ID, p.idea.Title
+		}
+	}
+	newState := i.State
+	if state != "" {
+		newState = state
+	}
+	if (op.State != "" || op.Kind != "" || op.Purpose != "") && purposeID == "" && b.kinds.PurposeRequiredFor(effectiveKind, newState) {
+		return fmt.Errorf("%s ideas require a purpose to be %s: give purpose",
+			effectiveKind, b.kinds.DisplayState(newState, effectiveKind))
+	}
+
+	renamed := op.Title != "" && op.Title != i.Title
+	if op.Title != "" {
+		i.Title = op.Title
+	}
+	if op.Kind != "" {
+		i.Kind = op.Kind
+	}
+	if op.Maturity != "" {
+		i.Maturity = op.Maturity
+	}
+	i.State = newState
+	i.PlannedFor = plannedFor
+	i.PurposeID, i.PurposeName = purposeID, purposeName
+	if op.Body != "" {
+		bi.body = ReplaceDescription(bi.body, op.Body)
+	}
+	b.touch(bi)
+
+	// Keep the copied purpose_name on attached ideas in step with the title
+	if renamed && i.Kind == denote.KindPurpose {
+		for _, c := range b.ideas {
+			if c.idea.PurposeID == i.ID && c.idea.PurposeName != i.Title {
+				c.idea.PurposeName = i.Title
+				c.dirty = true
+			}
+		}
+	}
+	return nil
+}
+
+func (b *batch) applyTag(bi *batchIdea, op BatchOp) error {
+	tag := strings.TrimSpace(op.Tag)
+	if tag == "" {
+		return fmt.Errorf("tag required")
+	}
+	i := bi.idea
+	if op.Remove {
+		var kept []string
+		for _, t := range i.Tags {
+			if t != tag {
+				kept = append(kept, t)
+			}
+		}
+		i.Tags = kept
+	} else if !containsString(i.Tags, tag) {
+		i.Tags = append(i.Tags, tag)
+	}
+	b.touch(bi)
+	return nil
+}
+
+func (b *batch) applyLink(op BatchOp) (*batchIdea, error) {
+	first, err := b.resolve(op.ID)
+	if err != nil {
+		return nil, fmt.Errorf("first idea: %w", err)
+	}
+	second, err := b.resolve(op.Target)
+	if err != nil {
+		return nil, fmt.Errorf("target idea: %w", err)
+	}
+	if first == second {
+		return nil, fmt.Errorf("cannot link an idea to itself")
+	}
+	if !containsString(first.idea.RelatedIdeas, second.idea.ID) {
+		acore.AddRelation(&first.idea.RelatedIdeas, second.idea.ID)
+		b.touch(first)
+	}
+	if !containsString(second.idea.RelatedIdeas, first.idea.ID) {
+		acore.AddRelation(&second.idea.RelatedIdeas, first.idea.ID)
+		b.touch(second)
+	}
+	return first, nil
+}
+
+func (b *batch) applyLog(op BatchOp) (*batchIdea, error) {
+	bi, err := b.resolve(op.ID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(op.Message) == "" {
+		return nil, fmt.Errorf("message required")
+	}
+	bi.body = AddLogEntry(bi.body, op.Message)
+	b.touch(bi)
+	b.post = append(b.post, postHook{hooks.PostLog, hooks.Payload{Idea: bi.idea, OldState: bi.idea.State, NewState: bi.idea.State, Message: op.Message}})
+	return bi, nil
+}
+
+func (b *batch) applyReject(bi *batchIdea, op BatchOp) error {
+	i := bi.idea
+	kind := kindOrDefault(i.Kind)
+	if !b.kinds.IsCompliant(kind, denote.StateRejected) {
+		return fmt.Errorf("%s ideas cannot be rejected; use an update with one of: %s",
+			kind, strings.Join(b.kinds.ValidStatesFor(kind), ", "))
+	}
+	if !op.Force {
+		if err := b.kinds.ValidateTransition(kind, i.State, denote.StateRejected); err != nil {
+			return fmt.Errorf("%w (set force to override)", err)
+		}
+	}
+	if strings.TrimSpace(op.Reason) == "" {
+		return fmt.Errorf("rejection reason cannot be empty")
+	}
+	i.State = denote.StateRejected
+	i.RejectedReason = op.Reason
+	b.touch(bi)
+	return nil
+}
+
+// commit writes the staged ideas, undoing every write if one fails.
+func (b *batch) commit() error {
+	var pending []*batchIdea
+	hasNew := false
+	for _, bi := range b.ideas {
+		if bi.dirty {
+			pending = append(pending, bi)
+			hasNew = hasNew || bi.isNew
+		}
+	}
+
+	if hasNew {
+		counter, err := denote.NewIDCounter(b.dir)
+		if err != nil {
+			return fmt.Errorf("failed to get ID counter: %w", err)
+		}
+		for _, bi := range pending {
+			if !bi.isNew {
+				continue
+			}
+			if bi.idea.IndexID, err = counter.Next(); err != nil {
+				return fmt.Errorf("failed to get next index ID: %w", err)
+			}
+		}
+	}
+
+	type original struct {
+		path string
+		data []byte
+		mode os.FileMode
+	}
+	var written []original
+	rollback := func(cause error) error {
+		var failed []string
+		for n := len(written) - 1; n >= 0; n-- {
+			o := written[n]
+			var err error
+			if o.data == nil {
+				if err = os.Remove(o.path); os.IsNotExist(err) {
+					err = nil
+				}
+			} else {
+				err = os.WriteFile(o.path, o.data, o.mode)
+			}
+			if err != nil {
+				failed = append(failed, o.path)
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("%w; rollback also failed for %s", cause, strings.Join(failed, ", "))
+		}
+		return fmt.Errorf("%w: all changes were rolled back", cause)
+	}
+
+	for _, bi := range pending {
+		path := bi.idea.FilePath
+		o := original{path: path}
+		if !bi.isNew {
+			info, err := os.Stat(path)
+			if err != nil {
+				return rollback(fmt.Errorf("failed to read idea #%d: %w", bi.idea.IndexID, err))
+			}
+			if o.data, err = os.ReadFile(path); err != nil {
+				return rollback(fmt.Errorf("failed to read idea #%d: %w", bi.idea.IndexID, err))
+			}
+			o.mode = info.Mode()
+		}
+		// Record before writing: a failed write may leave a partial file
+		written = append(written, o)
+		if err := denote.WriteIdeaFile(path, bi.idea, bi.body); err != nil {
+			return rollback(fmt.Errorf("failed to write idea #%d: %w", bi.idea.IndexID, err))
+		}
+	}
+	return nil
+}
+
+func containsString(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
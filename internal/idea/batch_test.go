@@ -0,0 +1,258 @@
+package idea
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mph-llm-experiments/anote/internal/config"
+	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/hooks"
+)
+
+func TestParseBatch_ArrayAndLines(t *testing.T) {
+	array := `[{"op":"new","title":"A","ref":"a"},{"op":"tag","id":"$a","tag":"x"}]`
+	lines := "{\"op\":\"new\",\"title\":\"A\",\"ref\":\"a\"}\n{\"op\":\"tag\",\"id\":\"$a\",\"tag\":\"x\"}\n"
+
+	for _, in := range []string{array, lines} {
+		ops, err := ParseBatch(strings.NewReader(in))
+		if err != nil {
+			t.Fatalf("ParseBatch(%q): %v", in, err)
+		}
+		if len(ops) != 2 || ops[0].Op != "new" || ops[1].ID != "$a" {
+			t.Errorf("ParseBatch(%q): got %+v", in, ops)
+		}
+	}
+
+	if _, err := ParseBatch(strings.NewReader(`[{"op":"new","titel":"A"}]`)); err == nil {
+		t.Error("expected error for an unknown field")
+	}
+	if _, err := ParseBatch(strings.NewReader("  ")); err == nil {
+		t.Error("expected error for no operations")
+	}
+}
+
+func TestRunBatch_RefsToNewIdeas(t *testing.T) {
+	dir := t.TempDir()
+	existing, err := CreateIdea(dir, "Existing", nil, "", "")
+	if err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+
+	ops := []BatchOp{
+		{Op: "new", Title: "Fresh", Ref: "fresh", Tags: []string{"work"}},
+		{Op: "tag", ID: "$fresh", Tag: "q3"},
+		{Op: "link", ID: "$fresh", Target: "1"},
+		{Op: "log", ID: "$fresh", Message: "started"},
+		{Op: "update", ID: "1", Title: "Existing, renamed"},
+	}
+	results, err := RunBatch(dir, ops, BatchOptions{})
+	if err != nil {
+		t.Fatalf("RunBatch: %v (%+v)", err, results)
+	}
+	for n, r := range results {
+		if !r.OK {
+			t.Errorf("result %d: %+v", n, r)
+		}
+	}
+	if results[0].IndexID != 2 || results[1].ID != results[0].ID {
+		t.Errorf("new idea results: %+v, %+v", results[0], results[1])
+	}
+
+	fresh, err := FindIdeaByID(dir, 2)
+	if err != nil {
+		t.Fatalf("FindIdeaByID: %v", err)
+	}
+	if strings.Join(fresh.Tags, ",") != "idea,work,q3" {
+		t.Errorf("tags: got %v", fresh.Tags)
+	}
+	if len(fresh.RelatedIdeas) != 1 || fresh.RelatedIdeas[0] != existing.ID {
+		t.Errorf("related ideas: got %v", fresh.RelatedIdeas)
+	}
+	if !strings.Contains(fresh.Content, "## Log\n") || !strings.Contains(fresh.Content, "started") {
+		t.Errorf("log entry missing from %q", fresh.Content)
+	}
+
+	reloaded, err := FindIdeaByID(dir, 1)
+	if err != nil {
+		t.Fatalf("FindIdeaByID: %v", err)
+	}
+	if reloaded.Title != "Existing, renamed" || len(reloaded.RelatedIdeas) != 1 {
+		t.Errorf("existing idea: title %q, related %v", reloaded.Title, reloaded.RelatedIdeas)
+	}
+}
+
+func TestRunBatch_InvalidOpAppliesNothing(t *testing.T) {
+	dir := t.TempDir()
+	existing, err := CreateIdea(dir, "Existing", nil, "", "")
+	if err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+	before, err := os.ReadFile(existing.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ops := []BatchOp{
+		{Op: "new", Title: "Never written", Ref: "n"},
+		{Op: "tag", ID: "1", Tag: "x"},
+		{Op: "update", ID: "1", State: "bogus"},
+		{Op: "log", ID: "$missing", Message: "hi"},
+	}
+	results, err := RunBatch(dir, ops, BatchOptions{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !results[0].OK || !results[1].OK || results[2].OK || results[3].OK {
+		t.Errorf("results: %+v", results)
+	}
+	if results[2].Error == "" || results[3].Error == "" {
+		t.Errorf("expected errors on invalid operations: %+v", results)
+	}
+
+	ideas, err := denote.NewScanner(dir).FindIdeas()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ideas) != 1 {
+		t.Errorf("got %d ideas, want 1", len(ideas))
+	}
+	after, err := os.ReadFile(existing.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Error("existing idea was modified")
+	}
+}
+
+func TestRunBatch_DryRun(t *testing.T) {
+	dir := t.TempDir()
+
+	results, err := RunBatch(dir, []BatchOp{{Op: "new", Title: "Draft only"}}, BatchOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("RunBatch: %v", err)
+	}
+	if !results[0].OK || results[0].IndexID != 0 {
+		t.Errorf("result: %+v", results[0])
+	}
+	ideas, err := denote.NewScanner(dir).FindIdeas()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ideas) != 0 {
+		t.Errorf("dry run wrote %d idea(s)", len(ideas))
+	}
+}
+
+func TestRunBatch_PurposeRename(t *testing.T) {
+	dir := t.TempDir()
+
+	ops := []BatchOp{
+		{Op: "new", Title: "Health", Kind: denote.KindPurpose, Ref: "p"},
+		{Op: "new", Title: "Run a marathon", Purpose: "$p", Ref: "a"},
+		{Op: "update", ID: "$p", Title: "Fitness"},
+	}
+	results, err := RunBatch(dir, ops, BatchOptions{})
+	if err != nil {
+		t.Fatalf("RunBatch: %v (%+v)", err, results)
+	}
+
+	attached, err := FindIdeaByID(dir, results[1].IndexID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attached.PurposeID != results[0].ID || attached.PurposeName != "Fitness" {
+		t.Errorf("purpose: got %q %q", attached.PurposeID, attached.PurposeName)
+	}
+}
+
+func TestRunBatch_Hooks(t *testing.T) {
+	dir := t.TempDir()
+	record := `echo "$ANOTE_EVENT $ANOTE_INDEX_ID" >> hooks.log`
+	hk, err := hooks.New(dir, map[string][]string{
+		hooks.PreStateChange:  {`grep -q '"title":"Keep' && exit 1 || exit 0`},
+		hooks.PostCreate:      {record},
+		hooks.PostStateChange: {record},
+		hooks.PostLog:         {record, "exit 3"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(dir, "hooks.log")
+
+	vetoed := []BatchOp{
+		{Op: "new", Title: "Keep me", Kind: denote.KindNote, Ref: "k"},
+		{Op: "update", ID: "$k", State: denote.StateArchived},
+	}
+	results, err := RunBatch(dir, vetoed, BatchOptions{Hooks: hk})
+	if err == nil || !results[0].OK || results[1].OK {
+		t.Fatalf("expected the pre-state-change hook to fail the batch: %v %+v", err, results)
+	}
+	if _, err := os.Stat(logPath); !os.IsNotExist(err) {
+		t.Error("post- hooks ran for a batch that was not applied")
+	}
+
+	ops := []BatchOp{
+		{Op: "new", Title: "Old note", Kind: denote.KindNote, Ref: "n"},
+		{Op: "update", ID: "$n", State: denote.StateArchived},
+		{Op: "log", ID: "$n", Message: "Shelved"},
+	}
+	results, err = RunBatch(dir, ops, BatchOptions{Hooks: hk})
+	if err != nil {
+		t.Fatalf("RunBatch: %v (%+v)", err, results)
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "post-create 1\npost-state-change 1\npost-log 1\n" {
+		t.Errorf("post- hooks: got %q", got)
+	}
+	if results[0].Warning != "" || results[2].Warning == "" {
+		t.Errorf("warnings: got %q and %q, want only the failed post-log", results[0].Warning, results[2].Warning)
+	}
+}
+
+func TestRunBatch_Rules(t *testing.T) {
+	dir := t.TempDir()
+	purpose, err := CreateIdea(dir, "Stay healthy", nil, denote.KindPurpose, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i, err := CreateIdea(dir, "Run a marathon", nil, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := []config.Rule{
+		{Name: "health", WhenTagAdded: "health", SetPurpose: "Stay healthy"},
+	}
+
+	// The purpose the rule attaches lets the idea leave seed
+	ops := []BatchOp{
+		{Op: "tag", ID: "2", Tag: "health"},
+		{Op: "update", ID: "2", State: "draft"},
+	}
+	results, err := RunBatch(dir, ops, BatchOptions{Rules: rules})
+	if err != nil {
+		t.Fatalf("RunBatch: %v (%+v)", err, results)
+	}
+	reloaded, err := denote.ParseIdeaFile(i.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.PurposeID != purpose.ID || reloaded.PurposeName != "Stay healthy" {
+		t.Errorf("purpose: got %q %q", reloaded.PurposeID, reloaded.PurposeName)
+	}
+	if !strings.Contains(reloaded.Content, `Rule "health": tag health added → purpose Stay healthy`) {
+		t.Errorf("fired rule not logged in %q", reloaded.Content)
+	}
+
+	// A rule that fails fails its operation, and the batch with it
+	if _, err := RunBatch(dir, []BatchOp{{Op: "tag", ID: "2", Tag: "hiking"}}, BatchOptions{
+		Rules: []config.Rule{{WhenTagAdded: "hiking", SetPurpose: "Missing"}},
+	}); err == nil {
+		t.Error("expected a rule naming a missing purpose to fail the batch")
+	}
+}
@@ -0,0 +1,69 @@
+package idea
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExtractContent extracts the body content after YAML frontmatter.
+func ExtractContent(fullContent string) string {
+	if !strings.HasPrefix(fullContent, "---\n") {
+		return fullContent
+	}
+
+	lines := strings.Split(fullContent, "\n")
+	for idx, line := range lines {
+		if idx == 0 {
+			continue
+		}
+		if line == "---" {
+			rest := strings.Join(lines[idx+1:], "\n")
+			return strings.TrimPrefix(rest, "\n")
+		}
+	}
+
+	return ""
+}
+
+// ReplaceDescription replaces the description portion of content (before ## Log),
+// preserving the log section.
+func ReplaceDescription(content, newDesc string) string {
+	logIdx := strings.Index(content, "\n## Log\n")
+	if logIdx == -1 {
+		logIdx = strings.Index(content, "## Log\n")
+		if logIdx == 0 {
+			// Content starts with ## Log, prepend description
+			return newDesc + "\n\n" + content
+		}
+		// No log section, just replace everything
+		return newDesc + "\n"
+	}
+	return newDesc + "\n" + content[logIdx:]
+}
+
+// AddLogEntry appends a timestamped entry to the ## Log section.
+func AddLogEntry(content, message string) string {
+	now := time.Now().Format("2006-01-02")
+	entry := fmt.Sprintf("- **%s** %s", now, message)
+
+	logIdx := strings.Index(content, "\n## Log\n")
+	if logIdx != -1 {
+		// Insert after the ## Log header
+		insertAt := logIdx + len("\n## Log\n")
+		return content[:insertAt] + entry + "\n" + content[insertAt:]
+	}
+
+	// Check if content starts with ## Log
+	if strings.HasPrefix(content, "## Log\n") {
+		insertAt := len("## Log\n")
+		return content[:insertAt] + entry + "\n" + content[insertAt:]
+	}
+
+	// No log section yet, append one
+	trimmed := strings.TrimRight(content, "\n")
+	if trimmed == "" {
+		return "## Log\n" + entry + "\n"
+	}
+	return trimmed + "\n\n## Log\n" + entry + "\n"
+}
@@ -0,0 +1,151 @@
+package idea
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/mph-llm-experiments/anote/internal/denote"
+)
+
+// CaptureItem is one idea read by ParseCapture: a new operation for
+// RunBatch and the input line it started on.
+type CaptureItem struct {
+	Line int
+	Op   BatchOp
+}
+
+var (
+	captureBullet  = regexp.MustCompile(`^([-*+]|\d+[.)])\s+`)
+	captureHeading = regexp.MustCompile(`^#{1,6}(\s|$)`)
+	captureTag     = regexp.MustCompile(`(^|\s)#(\pL[\pL\pN_/-]*)`)
+	capturePurpose = regexp.MustCompile(`(^|\s)@(?:"([^"]*)"|(\S+))`)
+)
+
+// ParseCapture reads brain-dump input, one idea per line or per markdown
+// bullet. Lines indented under an item are its body, dedented; blank lines
+// and markdown headings are skipped. In each title a leading "kind:" naming
+// a kind in kinds sets the kind, #tag adds a tag, and @purpose (or
+// @"two words") attaches a purpose by index_id, ULID or title:
+//
+//   - belief: Trust comes before autonomy #work @Career
+//     Seen on three teams this year.
+//   - Run a 10k #health @Health
+func ParseCapture(r io.Reader, kinds *denote.KindsConfig) ([]CaptureItem, error) {
+	type pending struct {
+		line   int
+		indent int
+		text   string
+		body   []string
+	}
+	var items []*pending
+	var cur *pending
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimRight(sc.Text(), " \t\r")
+		text := strings.TrimLeft(line, " \t")
+		indent := len(line) - len(text)
+
+		if text == "" {
+			if cur != nil {
+				cur.body = append(cur.body, "")
+			}
+			continue
+		}
+		if cur != nil && indent > cur.indent {
+			cur.body = append(cur.body, line)
+			continue
+		}
+		if captureHeading.MatchString(text) {
+			cur = nil
+			continue
+		}
+		cur = &pending{line: n, indent: indent, text: captureBullet.ReplaceAllString(text, "")}
+		items = append(items, cur)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no ideas in input")
+	}
+
+	out := make([]CaptureItem, 0, len(items))
+	for _, p := range items {
+		op, err := parseCaptureTitle(p.text, kinds)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", p.line, err)
+		}
+		op.Body = dedent(p.body)
+		out = append(out, CaptureItem{Line: p.line, Op: op})
+	}
+	return out, nil
+}
+
+// parseCaptureTitle splits the kind, tags and purpose out of one item.
+func parseCaptureTitle(text string, kinds *denote.KindsConfig) (BatchOp, error) {
+	op := BatchOp{Op: "new"}
+
+	if prefix, rest, ok := strings.Cut(text, ":"); ok {
+		if kind := strings.ToLower(strings.TrimSpace(prefix)); kinds.KindExists(kind) {
+			op.Kind = kind
+			text = rest
+		}
+	}
+
+	for _, m := range capturePurpose.FindAllStringSubmatch(text, -1) {
+		if op.Purpose != "" {
+			return op, fmt.Errorf("more than one @purpose")
+		}
+		op.Purpose = m[2] + m[3]
+	}
+	text = capturePurpose.ReplaceAllString(text, "$1")
+
+	for _, m := range captureTag.FindAllStringSubmatch(text, -1) {
+		if !containsString(op.Tags, m[2]) {
+			op.Tags = append(op.Tags, m[2])
+		}
+	}
+	text = captureTag.ReplaceAllString(text, "$1")
+
+	op.Title = strings.Join(strings.Fields(text), " ")
+	if op.Title == "" {
+		return op, fmt.Errorf("title required")
+	}
+	return op, nil
+}
+
+// dedent removes the indentation common to lines and the blank lines
+// around them.
+func dedent(lines []string) string {
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	common := -1
+	for _, l := range lines {
+		if l == "" {
+			continue
+		}
+		if indent := len(l) - len(strings.TrimLeft(l, " \t")); common < 0 || indent < common {
+			common = indent
+		}
+	}
+	out := make([]string, len(lines))
+	for n, l := range lines {
+		if l != "" {
+			out[n] = l[common:]
+		}
+	}
+	return strings.Join(out, "\n")
+}
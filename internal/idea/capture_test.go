@@ -0,0 +1,53 @@
+package idea
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mph-llm-experiments/anote/internal/denote"
+)
+
+func TestParseCapture(t *testing.T) {
+	in := `# Meeting notes
+
+- belief: Trust comes before autonomy #work @Career
+  Seen on three teams.
+
+    - nested point
+* Run a 10k #health #health
+1. note: Tokens expire hourly @"Stay healthy" mail me@example.com
+Fix issue #12 soon
+Remote work: async first
+`
+	items, err := ParseCapture(strings.NewReader(in), denote.DefaultKindsConfig())
+	if err != nil {
+		t.Fatalf("ParseCapture: %v", err)
+	}
+	if len(items) != 5 {
+		t.Fatalf("got %d items: %+v", len(items), items)
+	}
+
+	want := []BatchOp{
+		{Op: "new", Title: "Trust comes before autonomy", Kind: "belief", Tags: []string{"work"}, Purpose: "Career", Body: "Seen on three teams.\n\n  - nested point"},
+		{Op: "new", Title: "Run a 10k", Tags: []string{"health"}},
+		{Op: "new", Title: "Tokens expire hourly mail me@example.com", Kind: "note", Purpose: "Stay healthy"},
+		{Op: "new", Title: "Fix issue #12 soon"},
+		{Op: "new", Title: "Remote work: async first"},
+	}
+	for n, w := range want {
+		got := items[n].Op
+		if got.Title != w.Title || got.Kind != w.Kind || got.Purpose != w.Purpose || got.Body != w.Body ||
+			strings.Join(got.Tags, ",") != strings.Join(w.Tags, ",") {
+			t.Errorf("item %d:\n got %+v\nwant %+v", n, got, w)
+		}
+	}
+	if items[0].Line != 3 || items[1].Line != 7 {
+		t.Errorf("lines: got %d, %d", items[0].Line, items[1].Line)
+	}
+
+	for _, bad := range []string{"", "# only a heading\n", "- #tag @p\n", "- Idea @a @b\n"} {
+		if _, err := ParseCapture(strings.NewReader(bad), denote.DefaultKindsConfig()); err == nil {
+			t.Errorf("ParseCapture(%q): expected error", bad)
+		}
+	}
+}
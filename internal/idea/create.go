@@ -3,38 +3,94 @@ package idea
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/mph-llm-experiments/acore"
 	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/hooks"
 )
 
+// NewIdea holds the fields for a new idea. Kind defaults to aspiration.
+// Without a Body the body comes from the template named Template, or the
+// kind's template when Template is empty (see TemplatesDir).
+type NewIdea struct {
+	Title    string
+	Tags     []string
+	Kind     string
+	Body     string
+	Template string
+	Purpose  *denote.Idea  // optional purpose-kind idea to attach to
+	Hooks    *hooks.Runner // runs pre-create; post-create is the caller's
+}
+
 // CreateIdea creates a new idea file with YAML frontmatter.
+// The kind and its initial state are validated against kinds.json in dir.
 func CreateIdea(dir, title string, tags []string, kind string, body string) (*denote.Idea, error) {
+	return Create(dir, NewIdea{Title: title, Tags: tags, Kind: kind, Body: body})
+}
+
+// Create creates a new idea file from n.
+// The kind and its initial state are validated against kinds.json in dir.
+// A missing purpose for a purpose-required kind is an error only when
+// kinds.json sets purpose_on_create to "fail"; callers warn otherwise.
+// A failing pre-create hook aborts before an index ID is taken.
+func Create(dir string, n NewIdea) (*denote.Idea, error) {
+	kind := n.Kind
 	if kind == "" {
 		kind = denote.KindAspiration
 	}
 
+	kinds, err := denote.LoadKindsConfig(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kinds config: %w", err)
+	}
+	if !kinds.KindExists(kind) {
+		return nil, fmt.Errorf("invalid kind %q: use %s", kind, strings.Join(kinds.AllKinds(), ", "))
+	}
+	if n.Purpose == nil && kinds.PurposeRequired(kind) && kinds.FailOnMissingPurpose() {
+		return nil, fmt.Errorf("%s ideas require a purpose", kind)
+	}
+
+	idea, content, err := buildIdea(dir, kinds, kind, n)
+	if err != nil {
+		return nil, err
+	}
+	if err := n.Hooks.Run(hooks.PreCreate, hooks.Payload{Idea: idea, NewState: idea.State}); err != nil {
+		return nil, err
+	}
+
 	counter, err := denote.NewIDCounter(dir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ID counter: %w", err)
 	}
 
-	indexID, err := counter.Next()
+	idea.IndexID, err = counter.Next()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get next index ID: %w", err)
 	}
 
+	if err := denote.WriteIdeaFile(idea.FilePath, idea, content); err != nil {
+		return nil, fmt.Errorf("failed to write idea file: %w", err)
+	}
+
+	// Parse back to get consistent state (ModTime, etc.)
+	return denote.ParseIdeaFile(idea.FilePath)
+}
+
+// buildIdea returns the idea and body for n without an index ID. kind has
+// already been defaulted and validated.
+func buildIdea(dir string, kinds *denote.KindsConfig, kind string, n NewIdea) (*denote.Idea, string, error) {
 	id := acore.NewID()
 	now := acore.Now()
 
 	// Build filename: {ulid}--{slug}__idea.md
-	filename := denote.BuildIdeaFilename(id, title)
+	filename := denote.BuildIdeaFilename(id, n.Title)
 	path := filepath.Join(dir, filename)
 
 	// Ensure "idea" is in the tags array for frontmatter
-	allTags := make([]string, 0, len(tags)+1)
+	allTags := make([]string, 0, len(n.Tags)+1)
 	allTags = append(allTags, "idea")
-	for _, tag := range tags {
+	for _, tag := range n.Tags {
 		if tag != "idea" {
 			allTags = append(allTags, tag)
 		}
@@ -42,28 +98,25 @@ func CreateIdea(dir, title string, tags []string, kind string, body string) (*de
 
 	idea := &denote.Idea{}
 	idea.ID = id
-	idea.Title = title
-	idea.IndexID = indexID
+	idea.Title = n.Title
 	idea.Type = denote.TypeIdea
 	idea.Tags = allTags
 	idea.Created = now
 	idea.Modified = now
 	idea.Kind = kind
-	if denote.IsSimpleKind(kind) {
-		idea.State = denote.StateActive
-	} else {
-		idea.State = denote.StateSeed
+	idea.State = kinds.DefaultStateFor(kind)
+	if n.Purpose != nil {
+		idea.PurposeID = n.Purpose.ID
+		idea.PurposeName = n.Purpose.Title
 	}
 	idea.FilePath = path
 
-	content := ""
-	if body != "" {
-		content = body + "\n"
+	if n.Body != "" {
+		return idea, n.Body + "\n", nil
 	}
-	if err := denote.WriteIdeaFile(path, idea, content); err != nil {
-		return nil, fmt.Errorf("failed to write idea file: %w", err)
+	content, err := templateBody(dir, kind, n)
+	if err != nil {
+		return nil, "", err
 	}
-
-	// Parse back to get consistent state (ModTime, etc.)
-	return denote.ParseIdeaFile(path)
+	return idea, content, nil
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/hooks"
 )
 
 func TestCreateIdea_Basic(t *testing.T) {
@@ -182,3 +183,161 @@ func TestCreateIdea_ULIDFilenameFormat(t *testing.T) {
 		t.Errorf("ID should be 26 chars (ULID): got %d chars (%s)", len(idea.ID), idea.ID)
 	}
 }
+
+func TestCreateIdea_SimpleKindDefaultsActive(t *testing.T) {
+	dir := t.TempDir()
+
+	idea, err := CreateIdea(dir, "Tokens expire hourly", nil, "note", "")
+	if err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+
+	if idea.State != denote.StateActive {
+		t.Errorf("State: got %q, want %q", idea.State, denote.StateActive)
+	}
+}
+
+func TestCreateIdea_InvalidKind(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := CreateIdea(dir, "Nope", nil, "thought", ""); err == nil {
+		t.Error("expected error for kind not in kinds.json")
+	}
+}
+
+func TestCreateIdea_CustomKindFromConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	kinds := denote.DefaultKindsConfig()
+	kinds.Kinds["question"] = denote.KindEntry{
+		States:   []string{denote.StateDraft, denote.StateActive, denote.StateArchived},
+		Terminal: []string{denote.StateArchived},
+		Default:  denote.StateDraft,
+	}
+	if err := kinds.WriteToDir(dir); err != nil {
+		t.Fatalf("WriteToDir: %v", err)
+	}
+
+	idea, err := CreateIdea(dir, "Why is the build slow?", nil, "question", "")
+	if err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+
+	if idea.Kind != "question" {
+		t.Errorf("Kind: got %q, want %q", idea.Kind, "question")
+	}
+	if idea.State != denote.StateDraft {
+		t.Errorf("State: got %q, want %q", idea.State, denote.StateDraft)
+	}
+}
+
+func TestCreate_PurposeRequired(t *testing.T) {
+	dir := t.TempDir()
+
+	// Default config warns: creation succeeds without a purpose.
+	if _, err := CreateIdea(dir, "Loose aspiration", nil, denote.KindAspiration, ""); err != nil {
+		t.Fatalf("CreateIdea with warn mode: %v", err)
+	}
+
+	kinds := denote.DefaultKindsConfig()
+	kinds.PurposeOnCreate = denote.PurposeFail
+	if err := kinds.WriteToDir(dir); err != nil {
+		t.Fatalf("WriteToDir: %v", err)
+	}
+
+	if _, err := CreateIdea(dir, "Another loose one", nil, denote.KindAspiration, ""); err == nil {
+		t.Error("expected error creating aspiration without purpose in fail mode")
+	}
+	if _, err := CreateIdea(dir, "A note", nil, denote.KindNote, ""); err != nil {
+		t.Errorf("notes do not require a purpose: %v", err)
+	}
+
+	purpose, err := CreateIdea(dir, "Stay healthy", nil, denote.KindPurpose, "")
+	if err != nil {
+		t.Fatalf("CreateIdea purpose: %v", err)
+	}
+	attached, err := Create(dir, NewIdea{Title: "Run a marathon", Purpose: purpose})
+	if err != nil {
+		t.Fatalf("Create with purpose: %v", err)
+	}
+	if attached.PurposeID != purpose.ID {
+		t.Errorf("PurposeID: got %q, want %q", attached.PurposeID, purpose.ID)
+	}
+}
+
+func TestCreate_PreCreateHook(t *testing.T) {
+	dir := t.TempDir()
+	hk, err := hooks.New(dir, map[string][]string{
+		hooks.PreCreate: {`grep -q '"title":"Draft' && exit 1 || exit 0`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Create(dir, NewIdea{Title: "Draft thoughts", Hooks: hk}); err == nil {
+		t.Error("expected the pre-create hook to abort")
+	}
+	created, err := Create(dir, NewIdea{Title: "Mentoring guide", Hooks: hk})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.IndexID != 1 {
+		t.Errorf("IndexID: got %d, want 1 (an aborted create should not take an ID)", created.IndexID)
+	}
+}
+
+func TestCreate_Templates(t *testing.T) {
+	dir := t.TempDir()
+	os.Mkdir(filepath.Join(dir, TemplatesDir), 0755)
+	os.WriteFile(filepath.Join(dir, TemplatesDir, "belief.md"),
+		[]byte("## Statement\n{{.Title}}\n\n## Evidence for\n\n## Evidence against\n"), 0644)
+	os.WriteFile(filepath.Join(dir, TemplatesDir, "retro.md"),
+		[]byte("Retro {{.Date}} for {{.Purpose}}\n"), 0644)
+
+	read := func(i *denote.Idea) string {
+		data, err := os.ReadFile(i.FilePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ExtractContent(string(data))
+	}
+
+	belief, err := Create(dir, NewIdea{Title: "Trust comes first", Kind: denote.KindBelief})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := read(belief); !strings.HasPrefix(got, "## Statement\nTrust comes first\n") {
+		t.Errorf("kind template: got %q", got)
+	}
+
+	purpose, err := CreateIdea(dir, "Team health", nil, denote.KindPurpose, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	retro, err := Create(dir, NewIdea{Title: "Q3 retro", Template: "retro", Purpose: purpose})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := read(retro); !strings.Contains(got, "for Team health") || strings.Contains(got, "{{") {
+		t.Errorf("named template: got %q", got)
+	}
+
+	plain, err := Create(dir, NewIdea{Title: "Unstructured", Kind: denote.KindBelief, Template: NoTemplate})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := read(plain); got != "" {
+		t.Errorf("template none: got %q", got)
+	}
+
+	if _, err := Create(dir, NewIdea{Title: "Typo", Template: "retor"}); err == nil || !strings.Contains(err.Error(), "retro") {
+		t.Errorf("unknown template: got %v", err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "secret.md"), []byte("outside templates\n"), 0644)
+	for _, name := range []string{"../secret", "sub/retro", ".."} {
+		if _, err := Create(dir, NewIdea{Title: "Escape", Template: name}); err == nil || !strings.Contains(err.Error(), "not a path") {
+			t.Errorf("template %q: got %v", name, err)
+		}
+	}
+}
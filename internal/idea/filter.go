@@ -0,0 +1,283 @@
+package idea
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mph-llm-experiments/acore"
+	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/query"
+)
+
+// FilterFields lists the field names a filter query may use.
+var FilterFields = []string{"kind", "state", "tag", "maturity", "purpose", "title", "created", "modified", "planned_for"}
+
+// relativeDate matches durations such as 30d, 2w, 6m and 1y, meaning that
+// long before now.
+var relativeDate = regexp.MustCompile(`^(\d+)([dwmy])$`)
+
+// Filter is a compiled query (see package query) over ideas. Text terms
+// match title, tags and body; field terms are resolved against kinds.json
+// and the purposes among the ideas the filter was compiled with.
+type Filter struct {
+	expr   query.Expr
+	preds  map[query.Term]func(*searchDoc) bool
+	states bool
+}
+
+// CompileFilter parses q and resolves its field terms:
+//
+//   - kind:KIND, maturity:LEVEL (or none), tag:TAG, title:TEXT
+//   - state:STATE, where STATE may be a display label; a kind-specific label
+//     such as considering also implies the kind, as with list --state
+//   - purpose:PURPOSE by index_id, ULID or title, including its sub-purposes;
+//     purpose:none and purpose:any match unattached and attached ideas
+//   - created:, modified:, planned_for: compare by day against YYYY-MM-DD,
+//     today/yesterday/tomorrow, a natural date, or a duration ago (30d, 2w,
+//     6m, 1y), optionally prefixed with >, >=, < or <=. planned_for:none and
+//     planned_for:any test for the date's presence.
+//
+// entries supplies the purposes that purpose: terms may name (entries of
+// other kinds are ignored); now anchors relative dates.
+func CompileFilter(q string, kinds *denote.KindsConfig, entries []denote.IndexEntry, now time.Time) (*Filter, error) {
+	expr, err := query.Parse(q)
+	if err != nil {
+		return nil, err
+	}
+	f := &Filter{expr: expr, preds: make(map[query.Term]func(*searchDoc) bool)}
+	c := &filterCompiler{kinds: kinds, entries: entries, now: now}
+	for _, t := range query.Terms(expr) {
+		if _, done := f.preds[t]; done {
+			continue
+		}
+		pred, err := c.compile(t)
+		if err != nil {
+			return nil, err
+		}
+		f.preds[t] = pred
+		if t.Field == "state" {
+			f.states = true
+		}
+	}
+	return f, nil
+}
+
+// PurposeEntries returns index entries for the purposes among ideas, for
+// CompileFilter when the ideas are already loaded.
+func PurposeEntries(ideas []*denote.Idea) []denote.IndexEntry {
+	var entries []denote.IndexEntry
+	for _, i := range ideas {
+		if i.Kind == denote.KindPurpose {
+			entries = append(entries, denote.IndexEntry{ID: i.ID, IndexID: i.IndexID, Title: i.Title, Kind: i.Kind, PurposeID: i.PurposeID})
+		}
+	}
+	return entries
+}
+
+// Match reports whether i satisfies the filter.
+func (f *Filter) Match(i *denote.Idea) bool {
+	return f.match(newSearchDoc(i))
+}
+
+func (f *Filter) match(d *searchDoc) bool {
+	return f.expr.Eval(func(t query.Term) bool { return f.preds[t](d) })
+}
+
+// FiltersState reports whether the query has a state: term. Callers that
+// hide terminal states by default show them when it does, as with --state.
+func (f *Filter) FiltersState() bool {
+	return f.states
+}
+
+// filterCompiler turns terms into predicates.
+type filterCompiler struct {
+	kinds   *denote.KindsConfig
+	entries []denote.IndexEntry
+	now     time.Time
+}
+
+func (c *filterCompiler) compile(t query.Term) (func(*searchDoc) bool, error) {
+	if !t.IsField() {
+		return func(d *searchDoc) bool { return d.matches(t) }, nil
+	}
+	if t.Op != "" {
+		switch t.Field {
+		case "created", "modified", "planned_for":
+		default:
+			return nil, fmt.Errorf("%s: comparisons only apply to created, modified and planned_for", t.Field)
+		}
+	}
+
+	value := strings.ToLower(t.Value)
+	switch t.Field {
+	case "kind":
+		if !c.kinds.KindExists(value) {
+			return nil, fmt.Errorf("kind:%s: use %s", t.Value, strings.Join(c.kinds.AllKinds(), ", "))
+		}
+		return func(d *searchDoc) bool { return kindOrDefault(d.idea.Kind) == value }, nil
+
+	case "state":
+		canonical, impliedKind := c.kinds.ResolveDisplayState(value)
+		if !c.kinds.HasState(canonical) {
+			return nil, fmt.Errorf("state:%s: invalid state", t.Value)
+		}
+		return func(d *searchDoc) bool {
+			if d.idea.State != canonical {
+				return false
+			}
+			return impliedKind == "" || kindOrDefault(d.idea.Kind) == impliedKind
+		}, nil
+
+	case "tag":
+		return func(d *searchDoc) bool {
+			for _, tag := range d.idea.Tags {
+				if strings.EqualFold(tag, value) {
+					return true
+				}
+			}
+			return false
+		}, nil
+
+	case "maturity":
+		if value == "none" {
+			return func(d *searchDoc) bool { return d.idea.Maturity == "" }, nil
+		}
+		if !denote.IsValidMaturity(value) {
+			return nil, fmt.Errorf("maturity:%s: use crawl, walk, run or none", t.Value)
+		}
+		return func(d *searchDoc) bool { return d.idea.Maturity == value }, nil
+
+	case "title":
+		return func(d *searchDoc) bool { return strings.Contains(d.title, value) }, nil
+
+	case "purpose":
+		return c.compilePurpose(t.Value)
+
+	case "created", "modified", "planned_for":
+		return c.compileDate(t)
+	}
+	return nil, fmt.Errorf("unknown field %q: use %s", t.Field, strings.Join(FilterFields, ", "))
+}
+
+// compilePurpose resolves ref among the loaded purposes.
+func (c *filterCompiler) compilePurpose(ref string) (func(*searchDoc) bool, error) {
+	switch strings.ToLower(ref) {
+	case "none":
+		return func(d *searchDoc) bool { return d.idea.PurposeID == "" }, nil
+	case "any":
+		return func(d *searchDoc) bool { return d.idea.PurposeID != "" }, nil
+	}
+
+	var matches []denote.IndexEntry
+	n, numErr := strconv.Atoi(ref)
+	for _, e := range c.entries {
+		if e.Kind != denote.KindPurpose {
+			continue
+		}
+		if (numErr == nil && e.IndexID == n) || e.ID == ref || strings.EqualFold(e.Title, ref) {
+			matches = append(matches, e)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("purpose:%s: purpose not found", ref)
+	case 1:
+	default:
+		return nil, fmt.Errorf("purpose:%s: ambiguous, use its index_id or ULID", ref)
+	}
+
+	subtree := denote.PurposeSubtree(denote.PurposeParents(c.entries), matches[0].ID)
+	return func(d *searchDoc) bool { return subtree[d.idea.PurposeID] }, nil
+}
+
+// compileDate builds a by-day comparison on created, modified or planned_for.
+func (c *filterCompiler) compileDate(t query.Term) (func(*searchDoc) bool, error) {
+	field := t.Field
+	if field == "planned_for" && t.Op == "" {
+		switch strings.ToLower(t.Value) {
+		case "none":
+			return func(d *searchDoc) bool { return d.idea.PlannedFor == "" }, nil
+		case "any":
+			return func(d *searchDoc) bool { return d.idea.PlannedFor != "" }, nil
+		}
+	}
+
+	want, err := parseFilterDate(t.Value, c.now)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%s%s: %w", field, t.Op, t.Value, err)
+	}
+	op := t.Op
+	return func(d *searchDoc) bool {
+		got := ideaDate(d.idea, field)
+		if got == "" {
+			return false
+		}
+		switch op {
+		case ">":
+			return got > want
+		case ">=":
+			return got >= want
+		case "<":
+			return got < want
+		case "<=":
+			return got <= want
+		}
+		return got == want
+	}, nil
+}
+
+// parseFilterDate resolves a date value to YYYY-MM-DD.
+func parseFilterDate(v string, now time.Time) (string, error) {
+	switch strings.ToLower(v) {
+	case "today":
+		return now.Format("2006-01-02"), nil
+	case "yesterday":
+		return now.AddDate(0, 0, -1).Format("2006-01-02"), nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1).Format("2006-01-02"), nil
+	}
+	if m := relativeDate.FindStringSubmatch(strings.ToLower(v)); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		switch m[2] {
+		case "d":
+			return now.AddDate(0, 0, -n).Format("2006-01-02"), nil
+		case "w":
+			return now.AddDate(0, 0, -7*n).Format("2006-01-02"), nil
+		case "m":
+			return now.AddDate(0, -n, 0).Format("2006-01-02"), nil
+		default:
+			return now.AddDate(-n, 0, 0).Format("2006-01-02"), nil
+		}
+	}
+	if _, err := time.Parse("2006-01-02", v); err == nil {
+		return v, nil
+	}
+	if d, err := acore.ParseNaturalDate(v); err == nil {
+		return d, nil
+	}
+	return "", fmt.Errorf("invalid date (use YYYY-MM-DD, today, or a duration like 30d)")
+}
+
+// ideaDate returns the idea's created, modified or planned_for day as
+// YYYY-MM-DD in local time, or "" if unset.
+func ideaDate(i *denote.Idea, field string) string {
+	var v string
+	switch field {
+	case "created":
+		v = i.Created
+	case "modified":
+		v = i.Modified
+	default:
+		v = i.PlannedFor
+	}
+	if ts, err := time.Parse(time.RFC3339, v); err == nil {
+		return ts.Local().Format("2006-01-02")
+	}
+	if len(v) > 10 {
+		v = v[:10]
+	}
+	return v
+}
@@ -0,0 +1,104 @@
+package idea
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mph-llm-experiments/anote/internal/denote"
+)
+
+func filterIdea(id, title, kind, state string, tags ...string) *denote.Idea {
+	i := &denote.Idea{}
+	i.ID = id
+	i.Title = title
+	i.Kind = kind
+	i.State = state
+	i.Tags = tags
+	return i
+}
+
+func TestCompileFilter(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.Local)
+
+	health := filterIdea("p1", "Health", denote.KindPurpose, denote.StateActive)
+	health.IndexID = 1
+	sleep := filterIdea("p2", "Sleep", denote.KindPurpose, denote.StateActive)
+	sleep.PurposeID = health.ID
+
+	accepted := filterIdea("b1", "Walking helps", denote.KindBelief, denote.StateImplemented, "work")
+	accepted.PurposeID = sleep.ID
+	accepted.Modified = "2026-03-20T09:00:00Z"
+	accepted.Maturity = denote.MaturityWalk
+
+	considering := filterIdea("b2", "Naps are fine", denote.KindBelief, denote.StateActive, "work", "old")
+	considering.PurposeID = health.ID
+	considering.Modified = "2026-01-05T09:00:00Z"
+
+	plan := filterIdea("a1", "Run a marathon", "", denote.StateActive, "work")
+	plan.PlannedFor = "2026-04-15"
+	plan.Modified = "2026-03-30T09:00:00Z"
+
+	ideas := []*denote.Idea{health, sleep, accepted, considering, plan}
+	kinds := denote.DefaultKindsConfig()
+
+	tests := []struct {
+		q    string
+		want []string
+	}{
+		{"kind:belief", []string{"b1", "b2"}},
+		{"kind:aspiration", []string{"a1"}},
+		{"state:accepted", []string{"b1"}},
+		{"state:considering", []string{"b2"}},
+		{"state:active", []string{"p1", "p2", "b2", "a1"}},
+		{"tag:work -tag:old", []string{"b1", "a1"}},
+		{"maturity:walk", []string{"b1"}},
+		{"kind:belief maturity:none", []string{"b2"}},
+		{"purpose:Health", []string{"p2", "b1", "b2"}},
+		{"purpose:1 kind:belief", []string{"b1", "b2"}},
+		{"purpose:sleep", []string{"b1"}},
+		{"kind:belief purpose:none", nil},
+		{"modified:>30d", []string{"b1", "a1"}},
+		{"modified:<2026-02-01", []string{"b2"}},
+		{"modified:today", nil},
+		{"modified:yesterday", []string{"a1"}},
+		{"planned_for:any", []string{"a1"}},
+		{"planned_for:<=2026-04-15", []string{"a1"}},
+		{"planned_for:<2026-04-15", nil},
+		{"title:marathon OR naps", []string{"b2", "a1"}},
+		{"kind:belief (state:accepted OR state:considering) tag:work -tag:old modified:>30d purpose:Health", []string{"b1"}},
+	}
+	for _, tt := range tests {
+		f, err := CompileFilter(tt.q, kinds, PurposeEntries(ideas), now)
+		if err != nil {
+			t.Errorf("CompileFilter(%q): %v", tt.q, err)
+			continue
+		}
+		var got []string
+		for _, i := range ideas {
+			if f.Match(i) {
+				got = append(got, i.ID)
+			}
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.q, got, tt.want)
+			continue
+		}
+		for n := range got {
+			if got[n] != tt.want[n] {
+				t.Errorf("%q: got %v, want %v", tt.q, got, tt.want)
+				break
+			}
+		}
+	}
+
+	f, err := CompileFilter("state:considering", kinds, PurposeEntries(ideas), now)
+	if err != nil || !f.FiltersState() {
+		t.Errorf("FiltersState: want true for a state: term (err %v)", err)
+	}
+
+	for _, q := range []string{"color:red", "kind:widget", "state:pondering", "maturity:sprint", "purpose:Wealth", "created:someday", "tag:>work"} {
+		if _, err := CompileFilter(q, kinds, PurposeEntries(ideas), now); err == nil {
+			t.Errorf("CompileFilter(%q): expected error", q)
+		}
+	}
+}
@@ -9,33 +9,25 @@ import (
 // FindIdeaByID finds an idea by its sequential index ID.
 func FindIdeaByID(dir string, id int) (*denote.Idea, error) {
 	scanner := denote.NewScanner(dir)
-	ideas, err := scanner.FindIdeas()
+	idea, err := scanner.FindByIndexID(id)
 	if err != nil {
 		return nil, err
 	}
-
-	for _, idea := range ideas {
-		if idea.IndexID == id {
-			return idea, nil
-		}
+	if idea == nil {
+		return nil, fmt.Errorf("idea %d not found", id)
 	}
-
-	return nil, fmt.Errorf("idea %d not found", id)
+	return idea, nil
 }
 
 // FindIdeaByEntityID finds an idea by its entity ID (ULID or legacy Denote ID).
 func FindIdeaByEntityID(dir string, entityID string) (*denote.Idea, error) {
 	scanner := denote.NewScanner(dir)
-	ideas, err := scanner.FindIdeas()
+	idea, err := scanner.FindByEntityID(entityID)
 	if err != nil {
 		return nil, err
 	}
-
-	for _, idea := range ideas {
-		if idea.ID == entityID {
-			return idea, nil
-		}
+	if idea == nil {
+		return nil, fmt.Errorf("idea with ID %s not found", entityID)
 	}
-
-	return nil, fmt.Errorf("idea with ID %s not found", entityID)
+	return idea, nil
 }
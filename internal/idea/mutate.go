@@ -0,0 +1,254 @@
+package idea
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mph-llm-experiments/anote/internal/config"
+	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/hooks"
+)
+
+// Mutator saves edits to ideas. Saving through it applies the automation
+// rules from config, records each rule that fired in the idea's log, and
+// runs the state-change hooks around the write, so the CLI and the TUI
+// behave the same way.
+type Mutator struct {
+	dir   string
+	kinds *denote.KindsConfig
+	rules []config.Rule
+	hooks *hooks.Runner
+}
+
+// Change is an edit to one idea. Idea is the edited idea, whose FilePath
+// must be set. Before is the version it was edited from; when nil it is read
+// from disk, so set it when the file has already been changed, as by an
+// editor.
+type Change struct {
+	Idea        *denote.Idea
+	Before      *denote.Idea
+	Description string // replaces the description when non-empty
+
+	Fired   []string // log entries of the rules that fired, set by ApplyRules
+	Warning error    // a failed post-state-change hook, set by Save
+}
+
+// NewMutator returns a mutator for the ideas in dir. The rules are checked
+// against kinds up front so a typo fails every edit rather than none.
+func NewMutator(dir string, kinds *denote.KindsConfig, rules []config.Rule, hk *hooks.Runner) (*Mutator, error) {
+	checked := make([]config.Rule, len(rules))
+	for n, r := range rules {
+		r, err := checkRule(kinds, r)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", ruleName(n, r), err)
+		}
+		checked[n] = r
+	}
+	return &Mutator{dir: dir, kinds: kinds, rules: checked, hooks: hk}, nil
+}
+
+// checkRule validates r and resolves its state display labels.
+func checkRule(kinds *denote.KindsConfig, r config.Rule) (config.Rule, error) {
+	triggers := 0
+	for _, w := range []string{r.WhenTagAdded, r.WhenState, r.WhenKind} {
+		if w != "" {
+			triggers++
+		}
+	}
+	if triggers != 1 {
+		return r, fmt.Errorf("set exactly one of when_tag_added, when_state, when_kind")
+	}
+	if r.SetPurpose == "" && r.SetState == "" && r.SetMaturity == "" && r.AddTag == "" {
+		return r, fmt.Errorf("set at least one of set_purpose, set_state, set_maturity, add_tag")
+	}
+
+	for _, s := range []*string{&r.WhenState, &r.SetState} {
+		if *s == "" {
+			continue
+		}
+		*s, _ = kinds.ResolveDisplayState(*s)
+		if !denote.IsValidState(*s) && !kinds.HasState(*s) {
+			return r, fmt.Errorf("unknown state %q", *s)
+		}
+	}
+	if r.SetState == denote.StateRejected {
+		return r, fmt.Errorf("set_state cannot reject: rejecting needs a reason")
+	}
+	if r.WhenKind != "" && !kinds.KindExists(r.WhenKind) {
+		return r, fmt.Errorf("invalid kind %q: use %s", r.WhenKind, strings.Join(kinds.AllKinds(), ", "))
+	}
+	if r.SetMaturity != "" && !denote.IsValidMaturity(r.SetMaturity) {
+		return r, fmt.Errorf("invalid maturity %q: use crawl, walk, or run", r.SetMaturity)
+	}
+	return r, nil
+}
+
+func ruleName(n int, r config.Rule) string {
+	if r.Name != "" {
+		return fmt.Sprintf("%q", r.Name)
+	}
+	return fmt.Sprintf("#%d", n+1)
+}
+
+// ApplyRules applies the rules triggered by c's edit to c.Idea and records
+// the ones that changed something in c.Fired. A change made by one rule
+// can trigger another; each rule fires at most once. Calling it again is
+// harmless, so callers that validate the result call it before Save.
+func (m *Mutator) ApplyRules(c *Change) error {
+	if c.Before == nil {
+		before, err := denote.ParseIdeaFile(c.Idea.FilePath)
+		if err != nil {
+			return fmt.Errorf("failed to read idea: %w", err)
+		}
+		c.Before = before
+	}
+
+	fired := make([]bool, len(m.rules))
+	for {
+		progress := false
+		for n, r := range m.rules {
+			if fired[n] || !triggered(r, c.Before, c.Idea) {
+				continue
+			}
+			fired[n] = true
+			progress = true
+
+			changes, err := m.fire(r, c.Idea)
+			if err != nil {
+				return fmt.Errorf("rule %s: %w", ruleName(n, r), err)
+			}
+			if len(changes) == 0 {
+				continue
+			}
+			entry := "Rule"
+			if r.Name != "" {
+				entry += fmt.Sprintf(" %q", r.Name)
+			}
+			c.Fired = append(c.Fired, fmt.Sprintf("%s: %s → %s", entry, trigger(r), strings.Join(changes, ", ")))
+		}
+		if !progress {
+			return nil
+		}
+	}
+}
+
+// triggered reports whether r's trigger holds between before and after.
+func triggered(r config.Rule, before, after *denote.Idea) bool {
+	switch {
+	case r.WhenTagAdded != "":
+		return after.HasTag(r.WhenTagAdded) && !before.HasTag(r.WhenTagAdded)
+	case r.WhenState != "":
+		return after.State == r.WhenState && before.State != r.WhenState
+	default:
+		return kindOrDefault(after.Kind) == r.WhenKind && kindOrDefault(before.Kind) != r.WhenKind
+	}
+}
+
+func trigger(r config.Rule) string {
+	switch {
+	case r.WhenTagAdded != "":
+		return "tag " + r.WhenTagAdded + " added"
+	case r.WhenState != "":
+		return "state became " + r.WhenState
+	default:
+		return "kind became " + r.WhenKind
+	}
+}
+
+// fire applies r's changes to i and describes the ones that made a
+// difference. A maturity is skipped for kinds that do not track one.
+func (m *Mutator) fire(r config.Rule, i *denote.Idea) ([]string, error) {
+	kind := kindOrDefault(i.Kind)
+	var changes []string
+
+	if r.SetState != "" && i.State != r.SetState {
+		if !m.kinds.IsCompliant(kind, r.SetState) {
+			return nil, fmt.Errorf("state %q is not valid for kind %s", r.SetState, kind)
+		}
+		i.State = r.SetState
+		changes = append(changes, "state "+m.kinds.DisplayState(r.SetState, kind))
+	}
+	if r.SetMaturity != "" && i.Maturity != r.SetMaturity && m.kinds.UsesMaturity(kind) {
+		i.Maturity = r.SetMaturity
+		changes = append(changes, "maturity "+r.SetMaturity)
+	}
+	if r.SetPurpose != "" {
+		p, err := FindPurpose(m.dir, r.SetPurpose)
+		if err != nil {
+			return nil, err
+		}
+		if p.ID != i.ID && i.PurposeID != p.ID {
+			i.PurposeID = p.ID
+			i.PurposeName = p.Title
+			changes = append(changes, "purpose "+p.Title)
+		}
+	}
+	if r.AddTag != "" && !i.HasTag(r.AddTag) {
+		i.Tags = append(i.Tags, r.AddTag)
+		changes = append(changes, "tag "+r.AddTag)
+	}
+	return changes, nil
+}
+
+// Save applies the rules and writes c.Idea, replacing the description if
+// c.Description is set and logging each fired rule. A changed state runs
+// pre-state-change first, which aborts the save if it fails, and
+// post-state-change after.
+func (m *Mutator) Save(c *Change) error {
+	if err := m.ApplyRules(c); err != nil {
+		return err
+	}
+	i := c.Idea
+
+	stateChange := hooks.Payload{Idea: i, OldState: c.Before.State, NewState: i.State}
+	changed := stateChange.OldState != stateChange.NewState
+	if changed {
+		if err := m.hooks.Run(hooks.PreStateChange, stateChange); err != nil {
+			return err
+		}
+	}
+
+	i.Modified = nextModified(c.Before.Modified, time.Now())
+	if c.Description != "" || len(c.Fired) > 0 {
+		// The body on disk, which is Before's unless the file was edited
+		onDisk, err := denote.ParseIdeaFile(i.FilePath)
+		if err != nil {
+			return fmt.Errorf("failed to read idea: %w", err)
+		}
+		content := ExtractContent(onDisk.Content)
+		if c.Description != "" {
+			content = ReplaceDescription(content, c.Description)
+		}
+		// Entries go to the top of the log, so add the last rule first
+		for n := len(c.Fired) - 1; n >= 0; n-- {
+			content = AddLogEntry(content, c.Fired[n])
+		}
+		if err := denote.WriteIdeaFile(i.FilePath, i, content); err != nil {
+			return fmt.Errorf("failed to update idea: %w", err)
+		}
+	} else if err := denote.UpdateIdeaFrontmatter(i.FilePath, i); err != nil {
+		return fmt.Errorf("failed to update idea: %w", err)
+	}
+
+	if changed {
+		c.Warning = m.hooks.Run(hooks.PostStateChange, stateChange)
+	}
+	return nil
+}
+
+// Touch sets i's modified time for a write made now. The server uses modified
+// as an idea's ETag, so a write always moves it past the value i was read
+// with, by a second if the clock has not got there yet.
+func Touch(i *denote.Idea) {
+	i.Modified = nextModified(i.Modified, time.Now())
+}
+
+// nextModified returns the modified time for a write at now to an idea last
+// modified at prev.
+func nextModified(prev string, now time.Time) string {
+	if last, err := time.Parse(time.RFC3339, prev); err == nil && !now.Truncate(time.Second).After(last) {
+		return last.Add(time.Second).Format(time.RFC3339)
+	}
+	return now.Format(time.RFC3339)
+}
@@ -0,0 +1,147 @@
+package idea
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mph-llm-experiments/anote/internal/config"
+	"github.com/mph-llm-experiments/anote/internal/denote"
+)
+
+func TestNewMutator_InvalidRules(t *testing.T) {
+	kinds := denote.DefaultKindsConfig()
+	for _, r := range []config.Rule{
+		{SetMaturity: "run"},
+		{WhenState: "active", WhenKind: "note", SetMaturity: "run"},
+		{WhenState: "active"},
+		{WhenState: "bogus", SetMaturity: "run"},
+		{WhenKind: "bogus", SetState: "active"},
+		{WhenTagAdded: "x", SetMaturity: "sprint"},
+		{WhenTagAdded: "x", SetState: "rejected"},
+	} {
+		if _, err := NewMutator(t.TempDir(), kinds, []config.Rule{r}, nil); err == nil {
+			t.Errorf("expected error for rule %+v", r)
+		}
+	}
+}
+
+func TestMutator_Rules(t *testing.T) {
+	dir := t.TempDir()
+	kinds := denote.DefaultKindsConfig()
+	purpose, err := CreateIdea(dir, "Stay healthy", nil, denote.KindPurpose, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i, err := CreateIdea(dir, "Run a marathon", nil, "", "Training plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mut, err := NewMutator(dir, kinds, []config.Rule{
+		{Name: "health", WhenTagAdded: "health", SetPurpose: "Stay healthy"},
+		{WhenState: "implemented", SetMaturity: "run"},
+		{WhenKind: "note", SetState: "active"},
+		{WhenState: "active", AddTag: "live"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewMutator: %v", err)
+	}
+
+	i.Tags = append(i.Tags, "health")
+	change := &Change{Idea: i}
+	if err := mut.Save(change); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if i.PurposeID != purpose.ID || len(change.Fired) != 1 {
+		t.Errorf("tag rule: purpose %q, fired %v", i.PurposeID, change.Fired)
+	}
+
+	// A rule's change can trigger another: kind note forces active, which tags live
+	i.Kind = denote.KindNote
+	change = &Change{Idea: i}
+	if err := mut.Save(change); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if i.State != denote.StateActive || !i.HasTag("live") || len(change.Fired) != 2 {
+		t.Errorf("kind rule: state %q, tags %v, fired %v", i.State, i.Tags, change.Fired)
+	}
+
+	saved, err := denote.ParseIdeaFile(i.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := ExtractContent(saved.Content)
+	if !strings.HasPrefix(body, "Training plan") {
+		t.Errorf("description lost: %q", body)
+	}
+	for _, want := range []string{`Rule "health": tag health added → purpose Stay healthy`, "Rule: kind became note → state active", "Rule: state became active → tag live"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("log missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Index(body, "kind became note") > strings.Index(body, "state became active") {
+		t.Errorf("rules logged out of order:\n%s", body)
+	}
+
+	// Untriggered rules leave the body alone
+	i.Title = "Run two marathons"
+	change = &Change{Idea: i}
+	if err := mut.Save(change); err != nil || len(change.Fired) != 0 {
+		t.Errorf("rename: err %v, fired %v", err, change.Fired)
+	}
+}
+
+func TestMutator_SaveEditedFile(t *testing.T) {
+	dir := t.TempDir()
+	i, err := CreateIdea(dir, "Weekly review", nil, denote.KindNote, "Old notes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mut, err := NewMutator(dir, denote.DefaultKindsConfig(), []config.Rule{
+		{WhenState: "archived", AddTag: "done"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewMutator: %v", err)
+	}
+
+	// The file was rewritten by hand, so the version before the edit is given
+	edited := *i
+	edited.State = denote.StateArchived
+	if err := denote.WriteIdeaFile(i.FilePath, &edited, "New notes\n"); err != nil {
+		t.Fatal(err)
+	}
+	change := &Change{Idea: &edited, Before: i}
+	if err := mut.Save(change); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if !edited.HasTag("done") || len(change.Fired) != 1 {
+		t.Errorf("rule on edit: tags %v, fired %v", edited.Tags, change.Fired)
+	}
+
+	saved, err := denote.ParseIdeaFile(i.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := ExtractContent(saved.Content)
+	if !strings.HasPrefix(body, "New notes") || strings.Contains(body, "Old notes") || !strings.Contains(body, "state became archived") {
+		t.Errorf("body after edit:\n%s", body)
+	}
+}
+
+func TestNextModified(t *testing.T) {
+	now := time.Date(2026, 3, 17, 9, 30, 0, 400_000_000, time.UTC)
+	tests := []struct {
+		prev, want string
+	}{
+		{"", "2026-03-17T09:30:00Z"},
+		{"2026-03-17T09:29:59Z", "2026-03-17T09:30:00Z"},
+		{"2026-03-17T09:30:00Z", "2026-03-17T09:30:01Z"},
+		{"2026-03-17T09:30:05Z", "2026-03-17T09:30:06Z"},
+	}
+	for _, tt := range tests {
+		if got := nextModified(tt.prev, now); got != tt.want {
+			t.Errorf("nextModified(%q) = %q, want %q", tt.prev, got, tt.want)
+		}
+	}
+}
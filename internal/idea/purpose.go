@@ -0,0 +1,168 @@
+package idea
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mph-llm-experiments/anote/internal/denote"
+)
+
+// FindPurpose resolves a purpose-kind idea by index_id, ULID or title.
+// Title matches are case-insensitive and must be unambiguous.
+func FindPurpose(dir, ref string) (*denote.Idea, error) {
+	scanner := denote.NewScanner(dir)
+
+	var found *denote.Idea
+	var err error
+	if n, convErr := strconv.Atoi(ref); convErr == nil {
+		found, err = scanner.FindByIndexID(n)
+	} else {
+		found, err = scanner.FindByEntityID(ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if found == nil {
+		entries, err := scanner.Entries()
+		if err != nil {
+			return nil, err
+		}
+		var matches []denote.IndexEntry
+		for _, e := range entries {
+			if e.Kind == denote.KindPurpose && strings.EqualFold(e.Title, ref) {
+				matches = append(matches, e)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("purpose %q not found", ref)
+		case 1:
+			if found, err = scanner.FindByEntityID(matches[0].ID); err != nil {
+				return nil, err
+			}
+			if found == nil {
+				return nil, fmt.Errorf("purpose %q not found", ref)
+			}
+		default:
+			return nil, fmt.Errorf("purpose title %q is ambiguous: use its index_id or ULID", ref)
+		}
+	}
+
+	if found.Kind != denote.KindPurpose {
+		return nil, fmt.Errorf("idea #%d %q is not a purpose (kind: %s)", found.IndexID, found.Title, kindOrDefault(found.Kind))
+	}
+	return found, nil
+}
+
+// kindOrDefault returns kind, or aspiration when it is unset.
+func kindOrDefault(kind string) string {
+	if kind == "" {
+		return denote.KindAspiration
+	}
+	return kind
+}
+
+// AttachedTo returns the ideas whose purpose_id is purposeID.
+func AttachedTo(dir, purposeID string) ([]*denote.Idea, error) {
+	scanner := denote.NewScanner(dir)
+	entries, err := scanner.Entries()
+	if err != nil {
+		return nil, err
+	}
+	var attached []*denote.Idea
+	for _, e := range entries {
+		if e.PurposeID != purposeID {
+			continue
+		}
+		i, err := scanner.FindByEntityID(e.ID)
+		if err != nil {
+			return nil, err
+		}
+		if i != nil && i.PurposeID == purposeID {
+			attached = append(attached, i)
+		}
+	}
+	return attached, nil
+}
+
+// SyncPurposeName copies purpose's title into purpose_name on every idea
+// attached to it, e.g. after the purpose is renamed. It returns the number of
+// ideas updated. Modified is not bumped: the ideas themselves did not change.
+func SyncPurposeName(dir string, purpose *denote.Idea) (int, error) {
+	attached, err := AttachedTo(dir, purpose.ID)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, i := range attached {
+		if i.PurposeName == purpose.Title {
+			continue
+		}
+		i.PurposeName = purpose.Title
+		if err := denote.UpdateIdeaFrontmatter(i.FilePath, i); err != nil {
+			return n, fmt.Errorf("failed to update idea #%d: %w", i.IndexID, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// ReassignPurpose moves every idea attached to fromID onto to, or detaches
+// them when to is nil. It returns the number of ideas updated.
+func ReassignPurpose(dir, fromID string, to *denote.Idea) (int, error) {
+	if to != nil {
+		if err := CheckReassign(dir, fromID, to); err != nil {
+			return 0, err
+		}
+	}
+	attached, err := AttachedTo(dir, fromID)
+	if err != nil {
+		return 0, err
+	}
+	for n, i := range attached {
+		if to != nil {
+			i.PurposeID = to.ID
+			i.PurposeName = to.Title
+		} else {
+			i.PurposeID = ""
+			i.PurposeName = ""
+		}
+		Touch(i)
+		if err := denote.UpdateIdeaFrontmatter(i.FilePath, i); err != nil {
+			return n, fmt.Errorf("failed to update idea #%d: %w", i.IndexID, err)
+		}
+	}
+	return len(attached), nil
+}
+
+// CheckPurposeParent returns an error if making parent the parent purpose of
+// child would create a cycle, i.e. parent is child or one of its descendants.
+func CheckPurposeParent(dir string, child, parent *denote.Idea) error {
+	entries, err := denote.NewScanner(dir).Entries()
+	if err != nil {
+		return err
+	}
+	if denote.PurposeSubtree(denote.PurposeParents(entries), child.ID)[parent.ID] {
+		return fmt.Errorf("purpose %q is beneath %q: attaching would create a cycle", parent.Title, child.Title)
+	}
+	return nil
+}
+
+// CheckReassign returns an error if to is the purpose fromID or one beneath
+// it. Its sub-purposes are among the ideas attached to fromID, so moving them
+// there would attach a purpose to itself or leave them in a cycle.
+func CheckReassign(dir, fromID string, to *denote.Idea) error {
+	if to.ID == fromID {
+		return fmt.Errorf("cannot reassign ideas to the purpose being deleted")
+	}
+	entries, err := denote.NewScanner(dir).Entries()
+	if err != nil {
+		return err
+	}
+	if denote.PurposeSubtree(denote.PurposeParents(entries), fromID)[to.ID] {
+		return fmt.Errorf("cannot reassign ideas to %q: it is beneath the purpose being deleted", to.Title)
+	}
+	return nil
+}
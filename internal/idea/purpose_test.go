@@ -0,0 +1,194 @@
+package idea
+
+import (
+	"testing"
+
+	"github.com/mph-llm-experiments/anote/internal/denote"
+)
+
+func TestFindPurpose_ByIndexIDULIDAndTitle(t *testing.T) {
+	dir := t.TempDir()
+
+	purpose, err := CreateIdea(dir, "Photography", nil, denote.KindPurpose, "")
+	if err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+
+	for _, ref := range []string{"1", purpose.ID, "photography"} {
+		found, err := FindPurpose(dir, ref)
+		if err != nil {
+			t.Errorf("FindPurpose(%q): %v", ref, err)
+			continue
+		}
+		if found.ID != purpose.ID {
+			t.Errorf("FindPurpose(%q): got %q, want %q", ref, found.ID, purpose.ID)
+		}
+	}
+}
+
+func TestFindPurpose_RejectsNonPurpose(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := CreateIdea(dir, "Buy a camera", nil, "", ""); err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+
+	if _, err := FindPurpose(dir, "1"); err == nil {
+		t.Error("expected error when resolving an aspiration as a purpose")
+	}
+	if _, err := FindPurpose(dir, "Buy a camera"); err == nil {
+		t.Error("expected title lookup to ignore non-purpose ideas")
+	}
+}
+
+func TestFindPurpose_AmbiguousTitle(t *testing.T) {
+	dir := t.TempDir()
+
+	for i := 0; i < 2; i++ {
+		if _, err := CreateIdea(dir, "Health", nil, denote.KindPurpose, ""); err != nil {
+			t.Fatalf("CreateIdea: %v", err)
+		}
+	}
+
+	if _, err := FindPurpose(dir, "health"); err == nil {
+		t.Error("expected error for ambiguous purpose title")
+	}
+}
+
+func TestCreate_WithPurpose(t *testing.T) {
+	dir := t.TempDir()
+
+	purpose, err := CreateIdea(dir, "Health", nil, denote.KindPurpose, "")
+	if err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+
+	created, err := Create(dir, NewIdea{Title: "Run a 10k", Purpose: purpose})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if created.PurposeID != purpose.ID {
+		t.Errorf("PurposeID: got %q, want %q", created.PurposeID, purpose.ID)
+	}
+	if created.PurposeName != "Health" {
+		t.Errorf("PurposeName: got %q, want %q", created.PurposeName, "Health")
+	}
+}
+
+func TestSyncPurposeName(t *testing.T) {
+	dir := t.TempDir()
+
+	purpose, err := CreateIdea(dir, "Photography", nil, denote.KindPurpose, "")
+	if err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+	child, err := Create(dir, NewIdea{Title: "Buy a camera", Purpose: purpose})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	purpose.Title = "Film photography"
+	if err := denote.UpdateIdeaFrontmatter(purpose.FilePath, purpose); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := SyncPurposeName(dir, purpose)
+	if err != nil {
+		t.Fatalf("SyncPurposeName: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("SyncPurposeName updated %d ideas, want 1", n)
+	}
+	reloaded, err := denote.ParseIdeaFile(child.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.PurposeName != "Film photography" {
+		t.Errorf("PurposeName: got %q, want %q", reloaded.PurposeName, "Film photography")
+	}
+}
+
+func TestReassignPurpose(t *testing.T) {
+	dir := t.TempDir()
+
+	from, err := CreateIdea(dir, "Photography", nil, denote.KindPurpose, "")
+	if err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+	to, err := CreateIdea(dir, "Art", nil, denote.KindPurpose, "")
+	if err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+	child, err := Create(dir, NewIdea{Title: "Buy a camera", Purpose: from})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if n, err := ReassignPurpose(dir, from.ID, to); err != nil || n != 1 {
+		t.Fatalf("ReassignPurpose: n=%d err=%v", n, err)
+	}
+	reloaded, _ := denote.ParseIdeaFile(child.FilePath)
+	if reloaded.PurposeID != to.ID || reloaded.PurposeName != "Art" {
+		t.Errorf("after reassign: got (%q, %q), want (%q, Art)", reloaded.PurposeID, reloaded.PurposeName, to.ID)
+	}
+
+	if n, err := ReassignPurpose(dir, to.ID, nil); err != nil || n != 1 {
+		t.Fatalf("ReassignPurpose detach: n=%d err=%v", n, err)
+	}
+	reloaded, _ = denote.ParseIdeaFile(child.FilePath)
+	if reloaded.PurposeID != "" || reloaded.PurposeName != "" {
+		t.Errorf("after detach: got (%q, %q), want empty", reloaded.PurposeID, reloaded.PurposeName)
+	}
+}
+
+func TestReassignPurpose_BeneathDeleted(t *testing.T) {
+	dir := t.TempDir()
+
+	life, err := CreateIdea(dir, "Health", nil, denote.KindPurpose, "")
+	if err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+	goal, err := Create(dir, NewIdea{Title: "Fitness", Kind: denote.KindPurpose, Purpose: life})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	sub, err := Create(dir, NewIdea{Title: "Running", Kind: denote.KindPurpose, Purpose: goal})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	for _, to := range []*denote.Idea{life, goal, sub} {
+		if n, err := ReassignPurpose(dir, life.ID, to); err == nil || n != 0 {
+			t.Errorf("ReassignPurpose onto %s: n=%d err=%v, want an error", to.Title, n, err)
+		}
+	}
+	reloaded, _ := denote.ParseIdeaFile(goal.FilePath)
+	if reloaded.PurposeID != life.ID {
+		t.Errorf("rejected reassign moved %s to %q", goal.Title, reloaded.PurposeID)
+	}
+}
+
+func TestCheckPurposeParent(t *testing.T) {
+	dir := t.TempDir()
+
+	life, err := CreateIdea(dir, "Health", nil, denote.KindPurpose, "")
+	if err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+	goal, err := Create(dir, NewIdea{Title: "Fitness", Kind: denote.KindPurpose, Purpose: life})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	other, err := CreateIdea(dir, "Art", nil, denote.KindPurpose, "")
+	if err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+
+	if err := CheckPurposeParent(dir, life, goal); err == nil {
+		t.Error("expected error making a child purpose the parent of its parent")
+	}
+	if err := CheckPurposeParent(dir, goal, other); err != nil {
+		t.Errorf("moving a purpose under an unrelated one: %v", err)
+	}
+}
@@ -0,0 +1,260 @@
+package idea
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/query"
+)
+
+// maxSnippets caps the body lines reported per result.
+const maxSnippets = 3
+
+// snippetWidth is the longest snippet text returned; longer lines are cut
+// around the first match.
+const snippetWidth = 120
+
+// SearchResult is an idea matching a search query.
+type SearchResult struct {
+	Idea     *denote.Idea
+	Score    int
+	Snippets []Snippet
+}
+
+// Snippet is a body line containing a match. Line is the 1-based line number
+// in the idea file; Matches holds [start, end) byte offsets into Text.
+type Snippet struct {
+	Line    int      `json:"line"`
+	Text    string   `json:"text"`
+	Matches [][2]int `json:"matches"`
+}
+
+// Search returns the ideas in dir matching q across title, tags and body
+// (including log entries), most relevant first. q may also use the field
+// terms of CompileFilter; see package query for the syntax.
+func Search(dir, q string) ([]SearchResult, error) {
+	kinds, err := denote.LoadKindsConfig(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kinds config: %w", err)
+	}
+	ideas, err := denote.NewScanner(dir).FindIdeas()
+	if err != nil {
+		return nil, err
+	}
+	f, err := CompileFilter(q, kinds, PurposeEntries(ideas), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return SearchIdeas(ideas, f), nil
+}
+
+// SearchIdeas matches already loaded ideas against f and ranks them on its
+// text terms.
+func SearchIdeas(ideas []*denote.Idea, f *Filter) []SearchResult {
+	terms := query.PositiveTerms(f.expr)
+
+	var results []SearchResult
+	for _, i := range ideas {
+		doc := newSearchDoc(i)
+		if !f.match(doc) {
+			continue
+		}
+		results = append(results, SearchResult{
+			Idea:     i,
+			Score:    doc.score(terms),
+			Snippets: doc.snippets(terms),
+		})
+	}
+
+	sort.SliceStable(results, func(a, b int) bool {
+		if results[a].Score != results[b].Score {
+			return results[a].Score > results[b].Score
+		}
+		return results[a].Idea.ModTime.After(results[b].Idea.ModTime)
+	})
+	return results
+}
+
+// searchDoc is the lower-cased searchable text of one idea.
+type searchDoc struct {
+	idea  *denote.Idea
+	title string
+	tags  []string
+	body  string
+}
+
+func newSearchDoc(i *denote.Idea) *searchDoc {
+	d := &searchDoc{
+		idea:  i,
+		title: strings.ToLower(i.Title),
+		body:  strings.ToLower(i.Content),
+	}
+	for _, t := range i.Tags {
+		if t != "idea" {
+			d.tags = append(d.tags, strings.ToLower(t))
+		}
+	}
+	return d
+}
+
+// matches reports whether term occurs in the title, a tag or the body.
+func (d *searchDoc) matches(t query.Term) bool {
+	if strings.Contains(d.title, t.Text) || strings.Contains(d.body, t.Text) {
+		return true
+	}
+	for _, tag := range d.tags {
+		if strings.Contains(tag, t.Text) {
+			return true
+		}
+	}
+	return false
+}
+
+// score weighs title hits over tag hits over body hits. Phrases count double.
+func (d *searchDoc) score(terms []query.Term) int {
+	total := 0
+	for _, t := range terms {
+		s := 0
+		if strings.Contains(d.title, t.Text) {
+			s += 10
+		}
+		for _, tag := range d.tags {
+			if tag == t.Text {
+				s += 6
+			} else if strings.Contains(tag, t.Text) {
+				s += 3
+			}
+		}
+		n := strings.Count(d.body, t.Text)
+		if n > 5 {
+			n = 5
+		}
+		s += n
+		if t.Phrase {
+			s *= 2
+		}
+		total += s
+	}
+	return total
+}
+
+// snippets returns up to maxSnippets body lines containing a term, numbered
+// by their line in the file.
+func (d *searchDoc) snippets(terms []query.Term) []Snippet {
+	if len(terms) == 0 || d.idea.Content == "" {
+		return nil
+	}
+	offset := bodyLineOffset(d.idea)
+
+	var out []Snippet
+	for n, line := range strings.Split(d.idea.Content, "\n") {
+		lower := strings.ToLower(line)
+		var spans [][2]int
+		for _, t := range terms {
+			for from := 0; ; {
+				idx := strings.Index(lower[from:], t.Text)
+				if idx < 0 {
+					break
+				}
+				start := from + idx
+				spans = append(spans, [2]int{start, start + len(t.Text)})
+				from = start + len(t.Text)
+			}
+		}
+		if len(spans) == 0 {
+			continue
+		}
+		if len(lower) != len(line) {
+			// Case folding changed byte lengths; offsets would not line up.
+			text, _ := clipSnippet(line, spans[:1])
+			out = append(out, Snippet{Line: offset + n + 1, Text: text, Matches: [][2]int{}})
+			if len(out) == maxSnippets {
+				break
+			}
+			continue
+		}
+		text, spans := clipSnippet(line, mergeSpans(spans))
+		out = append(out, Snippet{Line: offset + n + 1, Text: text, Matches: spans})
+		if len(out) == maxSnippets {
+			break
+		}
+	}
+	return out
+}
+
+// mergeSpans sorts spans and joins overlapping ones.
+func mergeSpans(spans [][2]int) [][2]int {
+	sort.Slice(spans, func(a, b int) bool { return spans[a][0] < spans[b][0] })
+	merged := spans[:1]
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s[0] <= last[1] {
+			if s[1] > last[1] {
+				last[1] = s[1]
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// clipSnippet trims line to snippetWidth around its first match, adjusting
+// spans to the clipped text and dropping those cut off.
+func clipSnippet(line string, spans [][2]int) (string, [][2]int) {
+	trimmed := strings.TrimLeft(line, " \t")
+	shift := len(line) - len(trimmed)
+	line = strings.TrimRight(trimmed, " \t\r")
+
+	start := 0
+	if len(line) > snippetWidth {
+		start = spans[0][0] - shift - snippetWidth/3
+		if start < 0 {
+			start = 0
+		}
+		if start+snippetWidth > len(line) {
+			start = len(line) - snippetWidth
+		}
+		// Don't split a UTF-8 sequence
+		for start > 0 && line[start]&0xC0 == 0x80 {
+			start--
+		}
+		end := start + snippetWidth
+		for end < len(line) && line[end]&0xC0 == 0x80 {
+			end++
+		}
+		line = line[start:end]
+	}
+	shift += start
+
+	kept := [][2]int{}
+	for _, s := range spans {
+		a, b := s[0]-shift, s[1]-shift
+		if a < 0 || b > len(line) {
+			continue
+		}
+		kept = append(kept, [2]int{a, b})
+	}
+	return line, kept
+}
+
+// bodyLineOffset returns the number of file lines before the idea's body, so
+// snippet line numbers point into the file rather than the body.
+func bodyLineOffset(i *denote.Idea) int {
+	if strings.HasPrefix(i.Content, "---\n") || i.FilePath == "" {
+		return 0
+	}
+	raw, err := os.ReadFile(i.FilePath)
+	if err != nil {
+		return 0
+	}
+	idx := strings.Index(string(raw), i.Content)
+	if idx < 0 {
+		return 0
+	}
+	return strings.Count(string(raw[:idx]), "\n")
+}
@@ -0,0 +1,79 @@
+package idea
+
+import (
+	"testing"
+
+	"github.com/mph-llm-experiments/anote/internal/denote"
+)
+
+func TestSearch_RanksAndSnippets(t *testing.T) {
+	dir := t.TempDir()
+
+	body := "Some intro.\n\nMentors help new managers find their footing.\n\n## Log\n- **2026-01-02** Talked to a mentor"
+	inBody, err := CreateIdea(dir, "Weekly office hours", nil, denote.KindNote, body)
+	if err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+	inTitle, err := CreateIdea(dir, "Mentoring platform", []string{"career"}, denote.KindNote, "")
+	if err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+	if _, err := CreateIdea(dir, "Garden shed", []string{"home"}, denote.KindNote, "Needs a mentor? No."); err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+
+	results, err := Search(dir, "mentor -garden")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Search: got %d results, want 2", len(results))
+	}
+	if results[0].Idea.ID != inTitle.ID {
+		t.Errorf("title match should rank first, got %q", results[0].Idea.Title)
+	}
+
+	hit := results[1]
+	if hit.Idea.ID != inBody.ID {
+		t.Fatalf("second result: got %q", hit.Idea.Title)
+	}
+	if len(hit.Snippets) != 2 {
+		t.Fatalf("snippets: got %+v, want 2 (body line and log entry)", hit.Snippets)
+	}
+	s := hit.Snippets[0]
+	if s.Text != "Mentors help new managers find their footing." {
+		t.Errorf("snippet text: got %q", s.Text)
+	}
+	if len(s.Matches) != 1 || s.Text[s.Matches[0][0]:s.Matches[0][1]] != "Mentor" {
+		t.Errorf("snippet matches: got %v", s.Matches)
+	}
+	if s.Line <= 3 {
+		t.Errorf("snippet line %d should count the frontmatter", s.Line)
+	}
+}
+
+func TestSearch_TagsAndPhrases(t *testing.T) {
+	dir := t.TempDir()
+
+	tagged, err := CreateIdea(dir, "Quarterly review", []string{"leadership"}, denote.KindNote, "")
+	if err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+	if _, err := CreateIdea(dir, "Ladder", nil, denote.KindNote, "A career of climbing the ladder"); err != nil {
+		t.Fatalf("CreateIdea: %v", err)
+	}
+
+	results, err := Search(dir, "leadership")
+	if err != nil || len(results) != 1 || results[0].Idea.ID != tagged.ID {
+		t.Errorf("tag search: got %d results, err %v", len(results), err)
+	}
+
+	results, err = Search(dir, `"career ladder"`)
+	if err != nil || len(results) != 0 {
+		t.Errorf("phrase should not match separated words: got %d results, err %v", len(results), err)
+	}
+
+	if _, err := Search(dir, "(unbalanced"); err == nil {
+		t.Error("expected parse error")
+	}
+}
@@ -0,0 +1,101 @@
+package idea
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mph-llm-experiments/anote/internal/denote"
+)
+
+// SortKeys lists the orders accepted by list --sort, views and the TUI.
+var SortKeys = []string{"modified", "created", "title", "index", "state", "maturity", "planned_for"}
+
+// stateOrder ranks states by their place in the pipeline.
+var stateOrder = map[string]int{
+	denote.StateSeed:        0,
+	denote.StateDraft:       1,
+	denote.StateActive:      2,
+	denote.StateIterating:   3,
+	denote.StateImplemented: 4,
+	denote.StateArchived:    5,
+	denote.StateRejected:    6,
+	denote.StateDropped:     7,
+}
+
+var maturityOrder = map[string]int{
+	denote.MaturityCrawl: 0,
+	denote.MaturityWalk:  1,
+	denote.MaturityRun:   2,
+}
+
+// IsSortKey reports whether key is one of SortKeys.
+func IsSortKey(key string) bool {
+	for _, k := range SortKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Less returns the ordering for key in its natural direction: newest first
+// for modified and created, A-Z for title, ascending index_id, pipeline order
+// for state, crawl to run for maturity and soonest first for planned_for.
+// Ideas without a maturity or planned_for date sort last. Ties go to the most
+// recently modified. An unknown key orders by modified.
+func Less(key string) func(a, b *denote.Idea) bool {
+	var cmp func(a, b *denote.Idea) int
+	switch key {
+	case "created":
+		cmp = func(a, b *denote.Idea) int { return -strings.Compare(a.Created, b.Created) }
+	case "title":
+		cmp = func(a, b *denote.Idea) int {
+			return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		}
+	case "index":
+		cmp = func(a, b *denote.Idea) int { return a.IndexID - b.IndexID }
+	case "state":
+		cmp = func(a, b *denote.Idea) int { return rank(stateOrder, a.State) - rank(stateOrder, b.State) }
+	case "maturity":
+		cmp = func(a, b *denote.Idea) int { return rank(maturityOrder, a.Maturity) - rank(maturityOrder, b.Maturity) }
+	case "planned_for":
+		cmp = func(a, b *denote.Idea) int {
+			switch {
+			case a.PlannedFor == b.PlannedFor:
+				return 0
+			case a.PlannedFor == "":
+				return 1
+			case b.PlannedFor == "":
+				return -1
+			}
+			return strings.Compare(a.PlannedFor, b.PlannedFor)
+		}
+	default:
+		cmp = func(a, b *denote.Idea) int { return 0 }
+	}
+	return func(a, b *denote.Idea) bool {
+		if c := cmp(a, b); c != 0 {
+			return c < 0
+		}
+		return a.ModTime.After(b.ModTime)
+	}
+}
+
+// rank returns v's position in order, or len(order) if v is not in it.
+func rank(order map[string]int, v string) int {
+	if r, ok := order[v]; ok {
+		return r
+	}
+	return len(order)
+}
+
+// SortIdeas sorts ideas in place by key, reversed if reverse is set.
+func SortIdeas(ideas []*denote.Idea, key string, reverse bool) {
+	less := Less(key)
+	sort.SliceStable(ideas, func(i, j int) bool {
+		if reverse {
+			return less(ideas[j], ideas[i])
+		}
+		return less(ideas[i], ideas[j])
+	})
+}
@@ -0,0 +1,59 @@
+package idea
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mph-llm-experiments/anote/internal/denote"
+)
+
+func TestSortIdeas(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	a := filterIdea("a", "banana", denote.KindAspiration, denote.StateActive)
+	a.IndexID = 2
+	a.Created = "2026-01-02T00:00:00Z"
+	a.Maturity = denote.MaturityRun
+	a.ModTime = base.Add(3 * time.Hour)
+
+	b := filterIdea("b", "Apple", denote.KindAspiration, denote.StateSeed)
+	b.IndexID = 3
+	b.Created = "2026-01-03T00:00:00Z"
+	b.PlannedFor = "2026-04-01"
+	b.ModTime = base.Add(1 * time.Hour)
+
+	c := filterIdea("c", "cherry", denote.KindAspiration, denote.StateImplemented)
+	c.IndexID = 1
+	c.Created = "2026-01-01T00:00:00Z"
+	c.Maturity = denote.MaturityCrawl
+	c.PlannedFor = "2026-03-15"
+	c.ModTime = base.Add(2 * time.Hour)
+
+	tests := []struct {
+		key     string
+		reverse bool
+		want    string
+	}{
+		{"modified", false, "acb"},
+		{"modified", true, "bca"},
+		{"created", false, "bac"},
+		{"title", false, "bac"},
+		{"index", false, "cab"},
+		{"index", true, "bac"},
+		{"state", false, "bac"},
+		{"maturity", false, "cab"},
+		{"planned_for", false, "cba"},
+	}
+	for _, tt := range tests {
+		ideas := []*denote.Idea{a, b, c}
+		SortIdeas(ideas, tt.key, tt.reverse)
+		got := ideas[0].ID + ideas[1].ID + ideas[2].ID
+		if got != tt.want {
+			t.Errorf("SortIdeas(%s, reverse=%v): got %s, want %s", tt.key, tt.reverse, got, tt.want)
+		}
+	}
+
+	if !IsSortKey("planned_for") || IsSortKey("color") {
+		t.Error("IsSortKey: wrong answer")
+	}
+}
@@ -0,0 +1,90 @@
+package idea
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplatesDir is the directory inside the ideas directory that holds body
+// templates. templates/KIND.md is the default body for new ideas of that
+// kind; any templates/NAME.md can be picked by name.
+const TemplatesDir = "templates"
+
+// NoTemplate as a template name creates an idea with an empty body even
+// when its kind has a template.
+const NoTemplate = "none"
+
+// TemplateData is what a body template can use: {{.Title}}, {{.Kind}},
+// {{.Date}} (YYYY-MM-DD) and {{.Purpose}} (the purpose's title, if any).
+type TemplateData struct {
+	Title   string
+	Kind    string
+	Date    string
+	Purpose string
+}
+
+// Templates returns the names of the templates in dir, sorted.
+func Templates(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, TemplatesDir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(paths))
+	for _, p := range paths {
+		names = append(names, strings.TrimSuffix(filepath.Base(p), ".md"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// templateBody renders the body for n. An explicit n.Template must name a
+// template in the templates directory; otherwise the kind's template is used
+// if there is one.
+func templateBody(dir, kind string, n NewIdea) (string, error) {
+	name := n.Template
+	if name == NoTemplate {
+		return "", nil
+	}
+	if name == "" {
+		name = kind
+	}
+	if filepath.Base(name) != name || name == ".." {
+		return "", fmt.Errorf("invalid template %q: give a name from %s, not a path", name, filepath.Join(dir, TemplatesDir))
+	}
+
+	path := filepath.Join(dir, TemplatesDir, name+".md")
+	text, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to read template: %w", err)
+		}
+		if n.Template == "" {
+			return "", nil
+		}
+		names, _ := Templates(dir)
+		if len(names) == 0 {
+			return "", fmt.Errorf("unknown template %q: no templates in %s", name, filepath.Join(dir, TemplatesDir))
+		}
+		return "", fmt.Errorf("unknown template %q: use %s", name, strings.Join(names, ", "))
+	}
+
+	tmpl, err := template.New(name).Parse(string(text))
+	if err != nil {
+		return "", fmt.Errorf("invalid template %s: %w", path, err)
+	}
+	data := TemplateData{Title: n.Title, Kind: kind, Date: time.Now().Format("2006-01-02")}
+	if n.Purpose != nil {
+		data.Purpose = n.Purpose.Title
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid template %s: %w", path, err)
+	}
+	return buf.String(), nil
+}
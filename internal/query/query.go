@@ -0,0 +1,340 @@
+// Package query parses the filter and search expressions shared by
+// anote list -q, anote search and the TUI's / filter.
+//
+// A query is a list of terms joined by AND (implicit between adjacent terms)
+// and OR, with NOT or a leading "-" for negation and parentheses for grouping:
+//
+//	mentoring "career ladder" -(hiring OR recruiting)
+//	kind:belief (state:accepted OR state:considering) -tag:old modified:>30d
+//
+// Terms are words, double-quoted phrases, or field terms of the form
+// field:value, where value may be quoted and may start with a comparison
+// operator (>, >=, <, <=). What fields exist and what they mean is up to the
+// caller. Operators must be upper case; lower-case "and", "or" and "not" are
+// words.
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Term is a leaf of a query. Text terms have a lower-cased word or phrase in
+// Text. Field terms have a lower-cased Field, an optional comparison Op and
+// the Value as written.
+type Term struct {
+	Text   string
+	Phrase bool
+
+	Field string
+	Op    string
+	Value string
+}
+
+// IsField reports whether t is a field:value term.
+func (t Term) IsField() bool { return t.Field != "" }
+
+// Expr is a parsed query.
+type Expr interface {
+	// Eval reports whether the expression holds when match decides each term.
+	Eval(match func(Term) bool) bool
+
+	// walk calls fn for every term, reporting whether it sits under a NOT.
+	walk(negated bool, fn func(t Term, negated bool))
+}
+
+type termExpr struct{ term Term }
+
+type notExpr struct{ x Expr }
+
+type andExpr struct{ xs []Expr }
+
+type orExpr struct{ xs []Expr }
+
+func (e termExpr) Eval(match func(Term) bool) bool { return match(e.term) }
+
+func (e notExpr) Eval(match func(Term) bool) bool { return !e.x.Eval(match) }
+
+func (e andExpr) Eval(match func(Term) bool) bool {
+	for _, x := range e.xs {
+		if !x.Eval(match) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e orExpr) Eval(match func(Term) bool) bool {
+	for _, x := range e.xs {
+		if x.Eval(match) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e termExpr) walk(negated bool, fn func(Term, bool)) { fn(e.term, negated) }
+
+func (e notExpr) walk(negated bool, fn func(Term, bool)) { e.x.walk(!negated, fn) }
+
+func (e andExpr) walk(negated bool, fn func(Term, bool)) {
+	for _, x := range e.xs {
+		x.walk(negated, fn)
+	}
+}
+
+func (e orExpr) walk(negated bool, fn func(Term, bool)) {
+	for _, x := range e.xs {
+		x.walk(negated, fn)
+	}
+}
+
+// Terms returns every term in e, in query order.
+func Terms(e Expr) []Term {
+	var out []Term
+	e.walk(false, func(t Term, _ bool) { out = append(out, t) })
+	return out
+}
+
+// PositiveTerms returns the text terms in e that are not negated, in query
+// order. These are the terms worth ranking on and highlighting.
+func PositiveTerms(e Expr) []Term {
+	var out []Term
+	e.walk(false, func(t Term, negated bool) {
+		if !negated && !t.IsField() {
+			out = append(out, t)
+		}
+	})
+	return out
+}
+
+// token kinds
+const (
+	tokWord = iota
+	tokPhrase
+	tokField
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind  int
+	text  string
+	field string // for tokField
+}
+
+// tokenize splits s into words, phrases, operators and parentheses.
+func tokenize(s string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(':
+			toks = append(toks, token{kind: tokLParen})
+			i++
+		case c == ')':
+			toks = append(toks, token{kind: tokRParen})
+			i++
+		case c == '-' && i+1 < len(s) && s[i+1] != ' ':
+			toks = append(toks, token{kind: tokNot})
+			i++
+		case c == '"':
+			end := strings.IndexByte(s[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated phrase starting at %q", s[i:])
+			}
+			toks = append(toks, token{kind: tokPhrase, text: s[i+1 : i+1+end]})
+			i += end + 2
+		default:
+			j := i
+			for j < len(s) && !strings.ContainsRune(" \t\n()\"", rune(s[j])) {
+				j++
+			}
+			word := s[i:j]
+			if colon := strings.IndexByte(word, ':'); colon > 0 && isFieldName(word[:colon]) && !strings.HasPrefix(word[colon:], "://") {
+				value := word[colon+1:]
+				if value == "" || value == ">" || value == ">=" || value == "<" || value == "<=" {
+					// field:"quoted value"
+					if j < len(s) && s[j] == '"' {
+						end := strings.IndexByte(s[j+1:], '"')
+						if end < 0 {
+							return nil, fmt.Errorf("unterminated value starting at %q", s[i:])
+						}
+						value += s[j+1 : j+1+end]
+						j += end + 2
+					}
+				}
+				toks = append(toks, token{kind: tokField, field: strings.ToLower(word[:colon]), text: value})
+				i = j
+				continue
+			}
+			switch word {
+			case "AND":
+				toks = append(toks, token{kind: tokAnd})
+			case "OR":
+				toks = append(toks, token{kind: tokOr})
+			case "NOT":
+				toks = append(toks, token{kind: tokNot})
+			default:
+				toks = append(toks, token{kind: tokWord, text: word})
+			}
+			i = j
+		}
+	}
+	return toks, nil
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.toks) {
+		return token{}, false
+	}
+	return p.toks[p.pos], true
+}
+
+// isFieldName reports whether s looks like a field name: letters and underscores.
+func isFieldName(s string) bool {
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Parse parses a query string. An empty query is an error.
+func Parse(s string) (Expr, error) {
+	toks, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) == 0 {
+		return nil, fmt.Errorf("empty query")
+	}
+	p := &parser{toks: toks}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := p.peek(); ok {
+		if t.kind == tokRParen {
+			return nil, fmt.Errorf("unbalanced ')'")
+		}
+		return nil, fmt.Errorf("unexpected %q", t.text)
+	}
+	return e, nil
+}
+
+// parseOr parses: and ("OR" and)*
+func (p *parser) parseOr() (Expr, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	xs := []Expr{first}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != tokOr {
+			break
+		}
+		p.pos++
+		x, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		xs = append(xs, x)
+	}
+	if len(xs) == 1 {
+		return first, nil
+	}
+	return orExpr{xs}, nil
+}
+
+// parseAnd parses: unary (["AND"] unary)*
+func (p *parser) parseAnd() (Expr, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	xs := []Expr{first}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind == tokOr || t.kind == tokRParen {
+			break
+		}
+		if t.kind == tokAnd {
+			p.pos++
+		}
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		xs = append(xs, x)
+	}
+	if len(xs) == 1 {
+		return first, nil
+	}
+	return andExpr{xs}, nil
+}
+
+// parseUnary parses: ("NOT" | "-") unary | "(" or ")" | word | phrase | field
+func (p *parser) parseUnary() (Expr, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("query ends with an operator")
+	}
+	p.pos++
+	switch t.kind {
+	case tokNot:
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{x}, nil
+	case tokLParen:
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := p.peek(); !ok || t.kind != tokRParen {
+			return nil, fmt.Errorf("missing ')'")
+		}
+		p.pos++
+		return x, nil
+	case tokWord:
+		return termExpr{Term{Text: strings.ToLower(t.text)}}, nil
+	case tokPhrase:
+		text := strings.Join(strings.Fields(strings.ToLower(t.text)), " ")
+		if text == "" {
+			return nil, fmt.Errorf("empty phrase")
+		}
+		return termExpr{Term{Text: text, Phrase: true}}, nil
+	case tokField:
+		term := Term{Field: t.field}
+		term.Value = t.text
+		for _, op := range []string{">=", "<=", ">", "<"} {
+			if strings.HasPrefix(term.Value, op) {
+				term.Op = op
+				term.Value = term.Value[len(op):]
+				break
+			}
+		}
+		if term.Value == "" {
+			return nil, fmt.Errorf("%s: missing value", t.field)
+		}
+		return termExpr{term}, nil
+	case tokAnd, tokOr:
+		return nil, fmt.Errorf("operator without a term before it")
+	default:
+		return nil, fmt.Errorf("unbalanced ')'")
+	}
+}
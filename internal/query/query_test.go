@@ -0,0 +1,99 @@
+package query
+
+import "testing"
+
+func TestParse_Eval(t *testing.T) {
+	doc := map[string]bool{"coaching": true, "leadership": true, "career ladder": true}
+	has := func(term Term) bool { return doc[term.Text] }
+
+	tests := []struct {
+		q    string
+		want bool
+	}{
+		{"coaching", true},
+		{"Coaching leadership", true},
+		{"coaching hiring", false},
+		{"coaching AND hiring", false},
+		{"hiring OR coaching", true},
+		{"coaching -hiring", true},
+		{"coaching NOT leadership", false},
+		{`"career ladder"`, true},
+		{`"Career   Ladder" coaching`, true},
+		{"(hiring OR recruiting) coaching", false},
+		{"-(hiring OR recruiting) coaching", true},
+		{"coaching and", false}, // lower-case "and" is a word
+	}
+	for _, tt := range tests {
+		e, err := Parse(tt.q)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.q, err)
+			continue
+		}
+		if got := e.Eval(has); got != tt.want {
+			t.Errorf("Parse(%q).Eval = %v, want %v", tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, q := range []string{"", "  ", `"unterminated`, "(coaching", "coaching)", "coaching OR", "OR coaching", `""`} {
+		if _, err := Parse(q); err == nil {
+			t.Errorf("Parse(%q): expected error", q)
+		}
+	}
+}
+
+func TestPositiveTerms(t *testing.T) {
+	e, err := Parse(`mentoring "career ladder" -hiring NOT (a OR b)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	terms := PositiveTerms(e)
+	if len(terms) != 2 || terms[0].Text != "mentoring" || !terms[1].Phrase || terms[1].Text != "career ladder" {
+		t.Errorf("PositiveTerms: got %+v", terms)
+	}
+}
+
+func TestParse_FieldTerms(t *testing.T) {
+	e, err := Parse(`kind:belief (state:accepted OR State:considering) -tag:old modified:>30d purpose:"Be healthy"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	terms := Terms(e)
+	want := []Term{
+		{Field: "kind", Value: "belief"},
+		{Field: "state", Value: "accepted"},
+		{Field: "state", Value: "considering"},
+		{Field: "tag", Value: "old"},
+		{Field: "modified", Op: ">", Value: "30d"},
+		{Field: "purpose", Value: "Be healthy"},
+	}
+	if len(terms) != len(want) {
+		t.Fatalf("Terms: got %+v", terms)
+	}
+	for i := range want {
+		if terms[i] != want[i] {
+			t.Errorf("term %d: got %+v, want %+v", i, terms[i], want[i])
+		}
+	}
+	if len(PositiveTerms(e)) != 0 {
+		t.Errorf("PositiveTerms should skip field terms, got %+v", PositiveTerms(e))
+	}
+
+	// A colon after something that isn't a field name stays a word
+	e, err = Parse("10:30 http://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, term := range Terms(e) {
+		if term.IsField() {
+			t.Errorf("unexpected field term %+v", term)
+		}
+	}
+
+	for _, q := range []string{"kind:", "modified:>", `title:"unterminated`} {
+		if _, err := Parse(q); err == nil {
+			t.Errorf("Parse(%q): expected error", q)
+		}
+	}
+}
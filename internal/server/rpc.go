@@ -0,0 +1,455 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/idea"
+)
+
+// JSON-RPC error codes. The -320xx codes are anote's own.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+	codeNotFound       = -32001
+	codeConflict       = -32002
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC error. Data.Type is one of invalid_request,
+// unknown_method, invalid_params, validation, not_found, conflict and
+// internal; Data.Field names the offending parameter when there is one.
+type rpcError struct {
+	Code    int          `json:"code"`
+	Message string       `json:"message"`
+	Data    rpcErrorData `json:"data"`
+}
+
+type rpcErrorData struct {
+	Type  string `json:"type"`
+	Field string `json:"field,omitempty"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+func invalidParams(field, format string, args ...any) *rpcError {
+	return &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf(format, args...), Data: rpcErrorData{Type: "invalid_params", Field: field}}
+}
+
+// toRPCError maps errors from the shared operations onto JSON-RPC errors.
+func toRPCError(err error) *rpcError {
+	var re *rpcError
+	if errors.As(err, &re) {
+		return re
+	}
+	switch statusOf(err) {
+	case http.StatusBadRequest:
+		return &rpcError{Code: codeInvalidParams, Message: err.Error(), Data: rpcErrorData{Type: "invalid_params"}}
+	case http.StatusUnprocessableEntity:
+		return &rpcError{Code: codeInvalidParams, Message: err.Error(), Data: rpcErrorData{Type: "validation"}}
+	case http.StatusNotFound:
+		return &rpcError{Code: codeNotFound, Message: err.Error(), Data: rpcErrorData{Type: "not_found"}}
+	case http.StatusPreconditionFailed:
+		return &rpcError{Code: codeConflict, Message: err.Error(), Data: rpcErrorData{Type: "conflict"}}
+	}
+	return &rpcError{Code: codeInternalError, Message: err.Error(), Data: rpcErrorData{Type: "internal"}}
+}
+
+// Tool describes one RPC method and the JSON Schema of its params.
+type Tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+// kindInfo summarizes a kind from kinds.json for the tools listing.
+type kindInfo struct {
+	States          []string `json:"states"`
+	Terminal        []string `json:"terminal"`
+	Default         string   `json:"default"`
+	UsesMaturity    bool     `json:"uses_maturity"`
+	PurposeRequired bool     `json:"purpose_required"`
+}
+
+// toolsResult is the result of the tools method.
+type toolsResult struct {
+	Tools []Tool              `json:"tools"`
+	Kinds map[string]kindInfo `json:"kinds"`
+}
+
+// ServeRPC reads JSON-RPC 2.0 requests from r, one per line, and writes one
+// response line to w for each request that has an id. The tools method
+// describes the others; each takes its params as a JSON object.
+func (s *Server) ServeRPC(r io.Reader, w io.Writer) error {
+	in := bufio.NewReader(r)
+	out := bufio.NewWriter(w)
+	for {
+		line, readErr := in.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if resp := s.handleRPC(line); resp != nil {
+				data, err := json.Marshal(resp)
+				if err != nil {
+					return err
+				}
+				out.Write(data)
+				out.WriteByte('\n')
+				if err := out.Flush(); err != nil {
+					return err
+				}
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			return nil
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+// handleRPC answers one request line, or returns nil for a notification.
+func (s *Server) handleRPC(line []byte) *rpcResponse {
+	var req rpcRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		return &rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{
+			Code: codeParseError, Message: "parse error: " + err.Error(), Data: rpcErrorData{Type: "invalid_request"}}}
+	}
+	resp := &rpcResponse{JSONRPC: "2.0", ID: req.ID}
+	if len(req.ID) == 0 {
+		resp.ID = json.RawMessage("null")
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		resp.Error = &rpcError{Code: codeInvalidRequest, Message: `request needs "jsonrpc": "2.0" and a method`, Data: rpcErrorData{Type: "invalid_request"}}
+		return resp
+	}
+
+	result, err := s.call(req.Method, req.Params)
+	if len(req.ID) == 0 {
+		return nil
+	}
+	if err != nil {
+		resp.Error = toRPCError(err)
+	} else {
+		resp.Result = result
+	}
+	return resp
+}
+
+// call checks params against the method's schema and runs it.
+func (s *Server) call(method string, raw json.RawMessage) (any, error) {
+	kinds, err := denote.LoadKindsConfig(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	if method == "tools" {
+		return toolsResult{Tools: tools(kinds), Kinds: describeKinds(kinds)}, nil
+	}
+
+	var tool *Tool
+	for _, t := range tools(kinds) {
+		if t.Name == method {
+			tool = &t
+			break
+		}
+	}
+	if tool == nil {
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("unknown method %q: call tools for the list", method), Data: rpcErrorData{Type: "unknown_method"}}
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		raw = json.RawMessage("{}")
+	}
+	if err := checkParams(tool.InputSchema, raw); err != nil {
+		return nil, err
+	}
+
+	switch method {
+	case "list":
+		var p listParams
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, invalidParams("", "invalid params: %v", err)
+		}
+		return s.listIdeas(p)
+	case "search":
+		var p struct {
+			Query string `json:"q"`
+		}
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, invalidParams("", "invalid params: %v", err)
+		}
+		return s.searchIdeas(p.Query)
+	case "show":
+		var p struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, invalidParams("", "invalid params: %v", err)
+		}
+		out, _, err := s.showIdea(p.ID)
+		return out, err
+	}
+
+	var p struct {
+		idea.BatchOp
+		IfModified string `json:"if_modified"`
+	}
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, invalidParams("", "invalid params: %v", err)
+	}
+	p.Op = method
+	out, _, err := s.apply(p.BatchOp, p.IfModified)
+	return out, err
+}
+
+// checkParams verifies raw is an object with only the schema's properties,
+// the required ones present, and each of the JSON type the schema gives.
+func checkParams(schema map[string]any, raw json.RawMessage) error {
+	var params map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return invalidParams("", "params must be an object")
+	}
+	props := schema["properties"].(map[string]any)
+	for name, value := range params {
+		prop, ok := props[name].(map[string]any)
+		if !ok {
+			return invalidParams(name, "unknown parameter %q", name)
+		}
+		if !hasJSONType(value, prop["type"].(string)) {
+			return invalidParams(name, "%s must be of type %s", name, prop["type"])
+		}
+		if values, ok := prop["enum"].([]string); ok {
+			var v string
+			_ = json.Unmarshal(value, &v)
+			if !contains(values, v) {
+				return invalidParams(name, "%s: %q is not one of %s", name, v, strings.Join(values, ", "))
+			}
+		}
+	}
+	for _, name := range schema["required"].([]string) {
+		if _, ok := params[name]; !ok {
+			return invalidParams(name, "%s is required", name)
+		}
+	}
+	return nil
+}
+
+// hasJSONType reports whether value is a JSON value of the schema type.
+func hasJSONType(value json.RawMessage, typ string) bool {
+	var v any
+	if err := json.Unmarshal(value, &v); err != nil {
+		return false
+	}
+	switch typ {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == float64(int(f))
+	case "array":
+		items, ok := v.([]any)
+		if !ok {
+			return false
+		}
+		for _, item := range items {
+			if _, ok := item.(string); !ok {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// describeKinds lists each kind's states by display label.
+func describeKinds(kinds *denote.KindsConfig) map[string]kindInfo {
+	out := make(map[string]kindInfo)
+	for _, kind := range kinds.AllKinds() {
+		entry := kinds.Kinds[kind]
+		info := kindInfo{
+			Default:         kinds.DisplayState(entry.Default, kind),
+			UsesMaturity:    kinds.UsesMaturity(kind),
+			PurposeRequired: kinds.PurposeRequired(kind),
+			States:          []string{},
+			Terminal:        []string{},
+		}
+		for _, st := range entry.States {
+			info.States = append(info.States, kinds.DisplayState(st, kind))
+		}
+		for _, st := range entry.Terminal {
+			info.Terminal = append(info.Terminal, kinds.DisplayState(st, kind))
+		}
+		out[kind] = info
+	}
+	return out
+}
+
+// stateValues returns every canonical state and display label, sorted.
+func stateValues(kinds *denote.KindsConfig) []string {
+	seen := make(map[string]bool)
+	for _, kind := range kinds.AllKinds() {
+		for _, st := range kinds.ValidStatesFor(kind) {
+			seen[st] = true
+			seen[kinds.DisplayState(st, kind)] = true
+		}
+	}
+	values := make([]string, 0, len(seen))
+	for v := range seen {
+		if v != denote.StateRejected {
+			values = append(values, v)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+// tools returns the RPC methods with schemas built from kinds.
+func tools(kinds *denote.KindsConfig) []Tool {
+	str := func(desc string) map[string]any { return map[string]any{"type": "string", "description": desc} }
+	boolean := func(desc string) map[string]any { return map[string]any{"type": "boolean", "description": desc} }
+	integer := func(desc string) map[string]any {
+		return map[string]any{"type": "integer", "minimum": 0, "description": desc}
+	}
+	enum := func(desc string, values []string) map[string]any {
+		return map[string]any{"type": "string", "enum": values, "description": desc}
+	}
+	object := func(required []string, props map[string]any) map[string]any {
+		if required == nil {
+			required = []string{}
+		}
+		return map[string]any{"type": "object", "properties": props, "required": required, "additionalProperties": false}
+	}
+
+	id := str("index_id or ULID of the idea")
+	ifModified := str("Fail with a conflict unless the idea's modified timestamp is still this")
+	kind := enum("Kind of idea", kinds.AllKinds())
+	purpose := str("Purpose by index_id, ULID or title")
+
+	return []Tool{
+		{
+			Name:        "list",
+			Description: "List ideas, newest modified first; terminal states are hidden unless all is set or q has a state: term",
+			InputSchema: object(nil, map[string]any{
+				"q":       str("Filter query, e.g. kind:belief state:considering tag:work modified:>30d"),
+				"all":     boolean("Include terminal states"),
+				"sort":    enum("Sort order", idea.SortKeys),
+				"reverse": boolean("Reverse the sort order"),
+				"limit":   integer("Return at most this many ideas"),
+				"offset":  integer("Skip this many ideas first"),
+			}),
+		},
+		{
+			Name:        "search",
+			Description: "Full-text search over titles, tags and bodies, most relevant first",
+			InputSchema: object([]string{"q"}, map[string]any{
+				"q": str("Search query; terms are ANDed, with OR, NOT, -term, \"phrases\" and field terms"),
+			}),
+		},
+		{
+			Name:        "show",
+			Description: "Show one idea with its content",
+			InputSchema: object([]string{"id"}, map[string]any{"id": id}),
+		},
+		{
+			Name:        "new",
+			Description: "Create an idea in its kind's default state",
+			InputSchema: object([]string{"title"}, map[string]any{
+				"title":    str("Title"),
+				"kind":     kind,
+				"tags":     map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Tags"},
+				"body":     str("Markdown body"),
+				"template": str("Body template from the templates directory when body is empty; defaults to the kind's, none for no template"),
+				"purpose":  purpose,
+			}),
+		},
+		{
+			Name:        "update",
+			Description: "Update an idea's title, body, state, kind, maturity, purpose or planned date",
+			InputSchema: object([]string{"id"}, map[string]any{
+				"id":          id,
+				"title":       str("New title"),
+				"body":        str("New description; the ## Log section is kept"),
+				"state":       enum("New state (canonical or the kind's display label); reject ideas with reject", stateValues(kinds)),
+				"kind":        kind,
+				"maturity":    enum("Maturity, for kinds that use it", []string{denote.MaturityCrawl, denote.MaturityWalk, denote.MaturityRun}),
+				"purpose":     str("Purpose by index_id, ULID or title, or none to detach"),
+				"plan_for":    str("Planned date: YYYY-MM-DD, a natural date such as friday, or none"),
+				"force":       boolean("Skip the state transition check"),
+				"if_modified": ifModified,
+			}),
+		},
+		{
+			Name:        "log",
+			Description: "Add a dated entry to the idea's ## Log section",
+			InputSchema: object([]string{"id", "message"}, map[string]any{
+				"id":          id,
+				"message":     str("Log message"),
+				"if_modified": ifModified,
+			}),
+		},
+		{
+			Name:        "tag",
+			Description: "Add or remove a tag",
+			InputSchema: object([]string{"id", "tag"}, map[string]any{
+				"id":          id,
+				"tag":         str("Tag"),
+				"remove":      boolean("Remove the tag instead of adding it"),
+				"if_modified": ifModified,
+			}),
+		},
+		{
+			Name:        "link",
+			Description: "Link two ideas as related, both ways",
+			InputSchema: object([]string{"id", "target"}, map[string]any{
+				"id":          id,
+				"target":      str("index_id or ULID of the idea to link to"),
+				"if_modified": ifModified,
+			}),
+		},
+		{
+			Name:        "reject",
+			Description: "Reject an idea with a reason",
+			InputSchema: object([]string{"id", "reason"}, map[string]any{
+				"id":          id,
+				"reason":      str("Why the idea is rejected"),
+				"force":       boolean("Skip the state transition check"),
+				"if_modified": ifModified,
+			}),
+		},
+	}
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mph-llm-experiments/anote/internal/idea"
+)
+
+// rpc sends lines to a server over dir and decodes the response lines.
+func rpc(t *testing.T, dir string, lines ...string) []map[string]any {
+	t.Helper()
+	var out bytes.Buffer
+	if err := New(dir, nil, nil).ServeRPC(strings.NewReader(strings.Join(lines, "\n")), &out); err != nil {
+		t.Fatalf("ServeRPC: %v", err)
+	}
+	var responses []map[string]any
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var r map[string]any
+		if err := dec.Decode(&r); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		responses = append(responses, r)
+	}
+	return responses
+}
+
+func errorCode(r map[string]any) float64 {
+	e, _ := r["error"].(map[string]any)
+	code, _ := e["code"].(float64)
+	return code
+}
+
+func TestServeRPC_Tools(t *testing.T) {
+	resp := rpc(t, t.TempDir(), `{"jsonrpc":"2.0","id":1,"method":"tools"}`)
+	if len(resp) != 1 {
+		t.Fatalf("got %d responses", len(resp))
+	}
+	result := resp[0]["result"].(map[string]any)
+	names := map[string]bool{}
+	for _, tool := range result["tools"].([]any) {
+		names[tool.(map[string]any)["name"].(string)] = true
+	}
+	for _, want := range []string{"list", "search", "show", "new", "update", "log", "tag", "link", "reject"} {
+		if !names[want] {
+			t.Errorf("tools: missing %q", want)
+		}
+	}
+	belief := result["kinds"].(map[string]any)["belief"].(map[string]any)
+	if belief["default"] == "" || len(belief["states"].([]any)) == 0 {
+		t.Errorf("kinds: got %v", belief)
+	}
+}
+
+func TestServeRPC_CallsAndErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := idea.CreateIdea(dir, "Existing", nil, "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := rpc(t, dir,
+		`{"jsonrpc":"2.0","id":1,"method":"new","params":{"title":"Fresh","tags":["work"]}}`,
+		`{"jsonrpc":"2.0","method":"tag","params":{"id":"1","tag":"quiet"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"update","params":{"id":"1","titel":"typo"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"show","params":{"id":"99"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"log","params":{"id":"1","message":"hi","if_modified":"2000-01-01T00:00:00Z"}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"update","params":{"id":"1","state":"bogus"}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"nope"}`,
+		`not json`,
+	)
+	if len(resp) != 7 {
+		t.Fatalf("got %d responses, want 7 (notification answered?)", len(resp))
+	}
+
+	created := resp[0]["result"].(map[string]any)
+	if created["title"] != "Fresh" || created["index_id"] != float64(2) {
+		t.Errorf("new: got %v", resp[0])
+	}
+	for n, want := range []float64{codeInvalidParams, codeNotFound, codeConflict, codeInvalidParams, codeMethodNotFound, codeParseError} {
+		if got := errorCode(resp[n+1]); got != want {
+			t.Errorf("response %d: got code %v, want %v (%v)", n+2, got, want, resp[n+1])
+		}
+	}
+
+	i, err := idea.FindIdeaByID(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !i.HasTag("quiet") {
+		t.Errorf("notification was not applied: tags %v", i.Tags)
+	}
+}
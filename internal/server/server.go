@@ -0,0 +1,467 @@
+// Package server exposes the ideas directory as a local HTTP/JSON API for
+// anote serve and as line-delimited JSON-RPC for anote rpc. Writes go
+// through idea.RunBatch, so they are validated, and run rules and hooks, the
+// same way as the CLI commands, and each idea's ETag is its modified timestamp so that writers
+// sending If-Match (if_modified over RPC) cannot clobber each other.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mph-llm-experiments/anote/internal/config"
+	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/hooks"
+	"github.com/mph-llm-experiments/anote/internal/idea"
+)
+
+// Server serves the ideas in one directory.
+type Server struct {
+	dir   string
+	rules []config.Rule
+	hooks *hooks.Runner
+
+	// mu serializes writes so the If-Match check and the write happen
+	// together for requests made through the server.
+	mu sync.Mutex
+}
+
+// New returns a server for the ideas in dir whose writes apply rules and
+// run hk.
+func New(dir string, rules []config.Rule, hk *hooks.Runner) *Server {
+	return &Server{dir: dir, rules: rules, hooks: hk}
+}
+
+// Handler returns the API routes:
+//
+//	GET    /ideas                 list; q, all, sort, reverse, limit, offset
+//	GET    /search?q=QUERY        ranked full-text search
+//	GET    /ideas/{id}            one idea with its content
+//	POST   /ideas                 create: title, kind, tags, body, purpose
+//	PATCH  /ideas/{id}            update: title, body, state, kind, maturity, purpose, plan_for, force
+//	POST   /ideas/{id}/log        add a log entry: message
+//	POST   /ideas/{id}/tags       add a tag: tag
+//	DELETE /ideas/{id}/tags/{tag} remove a tag
+//	POST   /ideas/{id}/links      link both ways: target
+//	POST   /ideas/{id}/reject     reject: reason, force
+//
+// {id} is an index_id or ULID. Requests must name a loopback Host, and
+// writes must send Content-Type application/json, so that a web page open in
+// a browser can neither write (the content type forces a preflight that gets
+// no CORS answer) nor read through DNS rebinding.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ideas", s.list)
+	mux.HandleFunc("GET /search", s.search)
+	mux.HandleFunc("GET /ideas/{id}", s.show)
+	mux.HandleFunc("POST /ideas", s.create)
+	mux.HandleFunc("PATCH /ideas/{id}", s.write("update"))
+	mux.HandleFunc("POST /ideas/{id}/log", s.write("log"))
+	mux.HandleFunc("POST /ideas/{id}/tags", s.write("tag"))
+	mux.HandleFunc("DELETE /ideas/{id}/tags/{tag}", s.write("tag"))
+	mux.HandleFunc("POST /ideas/{id}/links", s.write("link"))
+	mux.HandleFunc("POST /ideas/{id}/reject", s.write("reject"))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isLoopbackHost(r.Host) {
+			writeError(w, fail(http.StatusForbidden, fmt.Errorf("host %q is not a loopback address: use localhost or 127.0.0.1", r.Host)))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// isLoopbackHost reports whether host, with or without a port, is localhost
+// or a loopback IP.
+func isLoopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ideaJSON is an idea as returned by the API: kind defaulted, state as its
+// display label, and content only for single ideas.
+type ideaJSON struct {
+	denote.Idea
+	Content string `json:"content,omitempty"`
+	Warning string `json:"warning,omitempty"` // a failed post- hook of a write
+}
+
+// searchResultJSON is a search hit.
+type searchResultJSON struct {
+	ideaJSON
+	Score    int            `json:"score"`
+	Snippets []idea.Snippet `json:"snippets"`
+}
+
+// errorJSON is the body of every error response.
+type errorJSON struct {
+	Error string `json:"error"`
+}
+
+// apiError is an error with the HTTP status it maps to.
+type apiError struct {
+	status int
+	err    error
+}
+
+func (e *apiError) Error() string { return e.err.Error() }
+
+func (e *apiError) Unwrap() error { return e.err }
+
+func fail(status int, err error) error {
+	return &apiError{status: status, err: err}
+}
+
+// statusOf returns the HTTP status for err, 500 unless it is an apiError.
+func statusOf(err error) int {
+	var ae *apiError
+	if errors.As(err, &ae) {
+		return ae.status
+	}
+	return http.StatusInternalServerError
+}
+
+func (s *Server) toJSON(kinds *denote.KindsConfig, i *denote.Idea, withContent bool) ideaJSON {
+	kind := i.Kind
+	if kind == "" {
+		kind = denote.KindAspiration
+	}
+	j := ideaJSON{Idea: *i}
+	j.Kind = kind
+	j.State = kinds.DisplayState(i.State, kind)
+	if withContent {
+		j.Content = idea.ExtractContent(i.Content)
+	}
+	return j
+}
+
+// etag returns the entity tag for i: its quoted modified timestamp.
+func etag(i *denote.Idea) string {
+	return strconv.Quote(modifiedOf(i))
+}
+
+func modifiedOf(i *denote.Idea) string {
+	if i.Modified == "" {
+		return i.Created
+	}
+	return i.Modified
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusOf(err), errorJSON{Error: err.Error()})
+}
+
+// find resolves an index_id or ULID.
+func (s *Server) find(ref string) (*denote.Idea, error) {
+	scanner := denote.NewScanner(s.dir)
+	var i *denote.Idea
+	var err error
+	if n, convErr := strconv.Atoi(ref); convErr == nil {
+		i, err = scanner.FindByIndexID(n)
+	} else {
+		i, err = scanner.FindByEntityID(ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if i == nil {
+		return nil, fail(http.StatusNotFound, fmt.Errorf("idea %s not found", ref))
+	}
+	return i, nil
+}
+
+// listParams are the options of a list request.
+type listParams struct {
+	Query   string `json:"q,omitempty"`
+	All     bool   `json:"all,omitempty"`
+	Sort    string `json:"sort,omitempty"`
+	Reverse bool   `json:"reverse,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+	Offset  int    `json:"offset,omitempty"`
+}
+
+// listIdeas filters, sorts and pages ideas as anote list does.
+func (s *Server) listIdeas(p listParams) ([]ideaJSON, error) {
+	kinds, err := denote.LoadKindsConfig(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	sortKey := p.Sort
+	if sortKey == "" {
+		sortKey = "modified"
+	} else if !idea.IsSortKey(sortKey) {
+		return nil, fail(http.StatusBadRequest, fmt.Errorf("invalid sort %q: use %s", sortKey, strings.Join(idea.SortKeys, ", ")))
+	}
+	if p.Limit < 0 || p.Offset < 0 {
+		return nil, fail(http.StatusBadRequest, fmt.Errorf("limit and offset cannot be negative"))
+	}
+
+	scanner := denote.NewScanner(s.dir)
+	var filter *idea.Filter
+	if p.Query != "" {
+		entries, err := scanner.Entries()
+		if err != nil {
+			return nil, err
+		}
+		if filter, err = idea.CompileFilter(p.Query, kinds, entries, time.Now()); err != nil {
+			return nil, fail(http.StatusBadRequest, fmt.Errorf("invalid query: %w", err))
+		}
+	}
+
+	ideas, err := scanner.FindIdeas()
+	if err != nil {
+		return nil, err
+	}
+	idea.SortIdeas(ideas, sortKey, p.Reverse)
+
+	out := []ideaJSON{}
+	skipped := 0
+	for _, i := range ideas {
+		kind := i.Kind
+		if kind == "" {
+			kind = denote.KindAspiration
+		}
+		// As with anote list, terminal states are hidden unless asked for
+		if !p.All && (filter == nil || !filter.FiltersState()) && kinds.IsTerminal(kind, i.State) {
+			continue
+		}
+		if filter != nil && !filter.Match(i) {
+			continue
+		}
+		if skipped < p.Offset {
+			skipped++
+			continue
+		}
+		out = append(out, s.toJSON(kinds, i, false))
+		if p.Limit > 0 && len(out) == p.Limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	p := listParams{
+		Query:   q.Get("q"),
+		All:     q.Get("all") == "true" || q.Get("all") == "1",
+		Sort:    q.Get("sort"),
+		Reverse: q.Get("reverse") == "true" || q.Get("reverse") == "1",
+	}
+	var err error
+	if p.Limit, p.Offset, err = pageParams(q.Get("limit"), q.Get("offset")); err != nil {
+		writeError(w, fail(http.StatusBadRequest, err))
+		return
+	}
+	out, err := s.listIdeas(p)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// pageParams parses the limit and offset query parameters.
+func pageParams(limit, offset string) (int, int, error) {
+	l, o := 0, 0
+	var err error
+	if limit != "" {
+		if l, err = strconv.Atoi(limit); err != nil || l < 0 {
+			return 0, 0, fmt.Errorf("limit: %q is not a count", limit)
+		}
+	}
+	if offset != "" {
+		if o, err = strconv.Atoi(offset); err != nil || o < 0 {
+			return 0, 0, fmt.Errorf("offset: %q is not a count", offset)
+		}
+	}
+	return l, o, nil
+}
+
+// searchIdeas runs a ranked search.
+func (s *Server) searchIdeas(query string) ([]searchResultJSON, error) {
+	if query == "" {
+		return nil, fail(http.StatusBadRequest, fmt.Errorf("q required"))
+	}
+	kinds, err := denote.LoadKindsConfig(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	results, err := idea.Search(s.dir, query)
+	if err != nil {
+		return nil, fail(http.StatusBadRequest, fmt.Errorf("invalid query: %w", err))
+	}
+	out := make([]searchResultJSON, 0, len(results))
+	for _, res := range results {
+		snippets := res.Snippets
+		if snippets == nil {
+			snippets = []idea.Snippet{}
+		}
+		out = append(out, searchResultJSON{ideaJSON: s.toJSON(kinds, res.Idea, false), Score: res.Score, Snippets: snippets})
+	}
+	return out, nil
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	out, err := s.searchIdeas(r.URL.Query().Get("q"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// showIdea returns one idea with its content.
+func (s *Server) showIdea(ref string) (ideaJSON, *denote.Idea, error) {
+	i, err := s.find(ref)
+	if err != nil {
+		return ideaJSON{}, nil, err
+	}
+	kinds, err := denote.LoadKindsConfig(s.dir)
+	if err != nil {
+		return ideaJSON{}, nil, err
+	}
+	return s.toJSON(kinds, i, true), i, nil
+}
+
+func (s *Server) show(w http.ResponseWriter, r *http.Request) {
+	out, i, err := s.showIdea(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("ETag", etag(i))
+	if match := r.Header.Get("If-None-Match"); match != "" && match == etag(i) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	var op idea.BatchOp
+	if !decodeBody(w, r, &op) {
+		return
+	}
+	op.Op = "new"
+	out, i, err := s.apply(op, "")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("ETag", etag(i))
+	w.Header().Set("Location", fmt.Sprintf("/ideas/%d", i.IndexID))
+	writeJSON(w, http.StatusCreated, out)
+}
+
+// write returns a handler applying op to the idea named in the path, after
+// checking If-Match against its ETag.
+func (s *Server) write(op string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body idea.BatchOp
+		if r.Method != http.MethodDelete && !decodeBody(w, r, &body) {
+			return
+		}
+		body.Op, body.ID = op, r.PathValue("id")
+		if tag := r.PathValue("tag"); tag != "" {
+			body.Tag, body.Remove = tag, true
+		}
+
+		ifModified := ""
+		if match := r.Header.Get("If-Match"); match != "" && match != "*" {
+			ifModified = match
+			if v, err := strconv.Unquote(match); err == nil {
+				ifModified = v
+			}
+		}
+
+		out, i, err := s.apply(body, ifModified)
+		if err != nil {
+			if statusOf(err) == http.StatusPreconditionFailed && i != nil {
+				w.Header().Set("ETag", etag(i))
+			}
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("ETag", etag(i))
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// apply runs op as a one-operation batch and returns the idea it acted on.
+// Unless ifModified is empty, the idea named by op.ID must still have that
+// modified timestamp; on a mismatch the current idea is returned with the
+// error.
+func (s *Server) apply(op idea.BatchOp, ifModified string) (ideaJSON, *denote.Idea, error) {
+	op.Ref = ""
+	if op.Op != "new" {
+		if op.ID == "" {
+			return ideaJSON{}, nil, fail(http.StatusBadRequest, fmt.Errorf("id required"))
+		}
+	} else {
+		op.ID = ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if op.ID != "" {
+		i, err := s.find(op.ID)
+		if err != nil {
+			return ideaJSON{}, nil, err
+		}
+		if ifModified != "" && ifModified != modifiedOf(i) {
+			return ideaJSON{}, i, fail(http.StatusPreconditionFailed, fmt.Errorf("idea #%d was modified at %s", i.IndexID, i.Modified))
+		}
+		op.ID = i.ID
+	}
+
+	results, err := idea.RunBatch(s.dir, []idea.BatchOp{op}, idea.BatchOptions{Rules: s.rules, Hooks: s.hooks})
+	if err != nil {
+		if len(results) == 1 && !results[0].OK {
+			return ideaJSON{}, nil, fail(http.StatusUnprocessableEntity, errors.New(results[0].Error))
+		}
+		return ideaJSON{}, nil, err
+	}
+	out, i, err := s.showIdea(results[0].ID)
+	out.Warning = results[0].Warning
+	return out, i, err
+}
+
+// decodeBody reads a JSON object into v, rejecting unknown fields.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+		writeError(w, fail(http.StatusUnsupportedMediaType, fmt.Errorf("content type must be application/json")))
+		return false
+	}
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, fail(http.StatusBadRequest, fmt.Errorf("invalid body: %w", err)))
+		return false
+	}
+	return true
+}
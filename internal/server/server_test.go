@@ -0,0 +1,189 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/idea"
+)
+
+func do(t *testing.T, h http.Handler, method, path, body string, header map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Host = "127.0.0.1:7787"
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_CreateShowAndList(t *testing.T) {
+	dir := t.TempDir()
+	h := New(dir, nil, nil).Handler()
+
+	rec := do(t, h, "POST", "/ideas", `{"title":"Mentoring guide","tags":["work"],"body":"Outline"}`, nil)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: %d %s", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("Location") != "/ideas/1" || rec.Header().Get("ETag") == "" {
+		t.Errorf("create headers: %v", rec.Header())
+	}
+
+	rec = do(t, h, "GET", "/ideas/1", "", nil)
+	var got ideaJSON
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("show: %v (%s)", err, rec.Body)
+	}
+	if got.Title != "Mentoring guide" || got.Kind != denote.KindAspiration || !strings.Contains(got.Content, "Outline") {
+		t.Errorf("show: got %+v", got)
+	}
+
+	rec = do(t, h, "GET", "/ideas?q=tag:work", "", nil)
+	var list []ideaJSON
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("list: %v (%s)", err, rec.Body)
+	}
+	if len(list) != 1 || list[0].Content != "" {
+		t.Errorf("list: got %+v", list)
+	}
+
+	if rec := do(t, h, "GET", "/ideas/42", "", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("missing idea: got %d", rec.Code)
+	}
+}
+
+func TestServer_IfMatch(t *testing.T) {
+	dir := t.TempDir()
+	h := New(dir, nil, nil).Handler()
+	if _, err := idea.CreateIdea(dir, "Existing", nil, "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	current := do(t, h, "GET", "/ideas/1", "", nil).Header().Get("ETag")
+
+	rec := do(t, h, "POST", "/ideas/1/tags", `{"tag":"x"}`, map[string]string{"If-Match": `"2000-01-01T00:00:00Z"`})
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("stale If-Match: got %d %s", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("ETag") != current {
+		t.Errorf("stale If-Match ETag: got %q, want %q", rec.Header().Get("ETag"), current)
+	}
+
+	rec = do(t, h, "POST", "/ideas/1/tags", `{"tag":"x"}`, map[string]string{"If-Match": current})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("current If-Match: got %d %s", rec.Code, rec.Body)
+	}
+	i, err := idea.FindIdeaByID(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !i.HasTag("x") {
+		t.Errorf("tag not added: %v", i.Tags)
+	}
+}
+
+func TestServer_IfMatchSameSecond(t *testing.T) {
+	dir := t.TempDir()
+	h := New(dir, nil, nil).Handler()
+	if _, err := idea.CreateIdea(dir, "Existing", nil, "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both writers read the idea, then write straight away
+	etag := do(t, h, "GET", "/ideas/1", "", nil).Header().Get("ETag")
+	first := do(t, h, "PATCH", "/ideas/1", `{"title":"First"}`, map[string]string{"If-Match": etag})
+	if first.Code != http.StatusOK {
+		t.Fatalf("first write: got %d %s", first.Code, first.Body)
+	}
+	if first.Header().Get("ETag") == etag {
+		t.Errorf("first write kept ETag %s", etag)
+	}
+	second := do(t, h, "PATCH", "/ideas/1", `{"title":"Second"}`, map[string]string{"If-Match": etag})
+	if second.Code != http.StatusPreconditionFailed {
+		t.Errorf("second write: got %d %s", second.Code, second.Body)
+	}
+
+	i, err := idea.FindIdeaByID(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i.Title != "First" {
+		t.Errorf("title: got %q, want First", i.Title)
+	}
+}
+
+func TestServer_CrossSite(t *testing.T) {
+	dir := t.TempDir()
+	h := New(dir, nil, nil).Handler()
+	if _, err := idea.CreateIdea(dir, "Existing", nil, "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// A form or fetch from a web page can send text/plain without a preflight
+	plain := map[string]string{"Content-Type": "text/plain"}
+	if rec := do(t, h, "POST", "/ideas", `{"title":"Injected"}`, plain); rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("text/plain create: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := do(t, h, "POST", "/ideas/1/log", `{"message":"Injected"}`, plain); rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("text/plain log: got %d %s", rec.Code, rec.Body)
+	}
+	if ideas, _ := denote.NewScanner(dir).FindIdeas(); len(ideas) != 1 {
+		t.Errorf("got %d ideas after text/plain writes, want 1", len(ideas))
+	}
+	charset := map[string]string{"Content-Type": "application/json; charset=utf-8"}
+	if rec := do(t, h, "POST", "/ideas/1/tags", `{"tag":"x"}`, charset); rec.Code != http.StatusOK {
+		t.Errorf("json with charset: got %d %s", rec.Code, rec.Body)
+	}
+
+	for host, want := range map[string]int{
+		"localhost:7787":    http.StatusOK,
+		"127.0.0.1":         http.StatusOK,
+		"[::1]:7787":        http.StatusOK,
+		"evil.example:7787": http.StatusForbidden,
+		"192.168.1.5:7787":  http.StatusForbidden,
+	} {
+		if rec := do(t, h, "GET", "/ideas/1", "", map[string]string{"Host": host}); rec.Code != want {
+			t.Errorf("Host %s: got %d, want %d", host, rec.Code, want)
+		}
+	}
+}
+
+func TestServer_ValidationErrors(t *testing.T) {
+	dir := t.TempDir()
+	h := New(dir, nil, nil).Handler()
+	if _, err := idea.CreateIdea(dir, "Existing", nil, "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		method, path, body string
+		want               int
+	}{
+		{"PATCH", "/ideas/1", `{"state":"bogus"}`, http.StatusUnprocessableEntity},
+		{"POST", "/ideas/1/reject", `{"reason":""}`, http.StatusUnprocessableEntity},
+		{"POST", "/ideas", `{"title":""}`, http.StatusUnprocessableEntity},
+		{"POST", "/ideas", `{"titel":"typo"}`, http.StatusBadRequest},
+		{"GET", "/ideas?sort=bogus", "", http.StatusBadRequest},
+	} {
+		rec := do(t, h, tc.method, tc.path, tc.body, nil)
+		if rec.Code != tc.want {
+			t.Errorf("%s %s %s: got %d, want %d (%s)", tc.method, tc.path, tc.body, rec.Code, tc.want, rec.Body)
+		}
+		var e errorJSON
+		if err := json.Unmarshal(rec.Body.Bytes(), &e); err != nil || e.Error == "" {
+			t.Errorf("%s %s: expected an error body, got %s", tc.method, tc.path, rec.Body)
+		}
+	}
+}
@@ -28,6 +28,7 @@ const (
 	ModeCompliancePrompt
 	ModeSortMenu
 	ModeFilterMenu
+	ModeViewMenu
 )
 
 // anote-specific status symbols.
@@ -42,16 +43,6 @@ const (
 	SymbolDropped     = "⨯"
 )
 
-// Kind symbols for list display.
-const (
-	SymbolAspiration = "↑"
-	SymbolBelief     = "◆"
-	SymbolPlan       = "→"
-	SymbolNote       = "·"
-	SymbolFact       = "="
-	SymbolPurpose    = "★"
-)
-
 // Editable field names.
 const (
 	FieldState    = "state"
@@ -61,4 +52,7 @@ const (
 	FieldTags     = "tags"
 	FieldLog      = "log"
 	FieldTitle    = "title"
+	FieldReassign = "reassign" // purpose picker for a deleted purpose's ideas
+	FieldPlanned  = "planned_for"
+	FieldQuery    = "query"
 )
@@ -8,23 +8,9 @@ import (
 	"github.com/mph-llm-experiments/anote/internal/denote"
 )
 
-// KindSymbol returns the single-character symbol for a kind.
-func KindSymbol(kind string) string {
-	switch kind {
-	case denote.KindAspiration:
-		return SymbolAspiration
-	case denote.KindBelief:
-		return SymbolBelief
-	case denote.KindPlan:
-		return SymbolPlan
-	case denote.KindNote:
-		return SymbolNote
-	case denote.KindFact:
-		return SymbolFact
-	case denote.KindPurpose:
-		return SymbolPurpose
-	}
-	return "?"
+// KindSymbol returns the single-character symbol for a kind, as declared in kinds.json.
+func KindSymbol(kinds *denote.KindsConfig, kind string) string {
+	return kinds.Symbol(kind)
 }
 
 // StateSymbol returns the single-character symbol for a state.
@@ -69,10 +55,10 @@ func stateColor(state string) string {
 
 // FormatListRow renders a single idea as a list row string.
 // selected applies the selected highlight color to the title.
-func FormatListRow(title, kind, state, maturity, purposeName string, selected bool, width int) string {
+func FormatListRow(kinds *denote.KindsConfig, title, kind, state, maturity, purposeName string, selected bool, width int) string {
 	kindSym := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(acoreui.ColorMuted)).
-		Render(KindSymbol(kind))
+		Render(KindSymbol(kinds, kind))
 
 	stateSym := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(stateColor(state))).
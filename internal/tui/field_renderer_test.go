@@ -4,28 +4,39 @@ import (
 	"testing"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mph-llm-experiments/anote/internal/denote"
 	"github.com/mph-llm-experiments/anote/internal/tui"
 	"github.com/muesli/termenv"
 )
 
 func TestKindSymbol_KnownKinds(t *testing.T) {
+	kinds := denote.DefaultKindsConfig()
 	tests := []struct{ kind, want string }{
-		{"aspiration", tui.SymbolAspiration},
-		{"belief", tui.SymbolBelief},
-		{"plan", tui.SymbolPlan},
-		{"note", tui.SymbolNote},
-		{"fact", tui.SymbolFact},
-		{"purpose", tui.SymbolPurpose},
+		{"aspiration", "↑"},
+		{"belief", "◆"},
+		{"plan", "→"},
+		{"note", "·"},
+		{"fact", "="},
+		{"purpose", "★"},
 		{"unknown", "?"},
 	}
 	for _, tt := range tests {
-		got := tui.KindSymbol(tt.kind)
+		got := tui.KindSymbol(kinds, tt.kind)
 		if got != tt.want {
 			t.Errorf("KindSymbol(%q) = %q, want %q", tt.kind, got, tt.want)
 		}
 	}
 }
 
+func TestKindSymbol_FromConfig(t *testing.T) {
+	kinds := denote.DefaultKindsConfig()
+	kinds.Kinds["decision"] = denote.KindEntry{States: []string{"active"}, Symbol: "⚖"}
+
+	if got := tui.KindSymbol(kinds, "decision"); got != "⚖" {
+		t.Errorf("KindSymbol(decision) = %q, want %q", got, "⚖")
+	}
+}
+
 func TestStateSymbol_KnownStates(t *testing.T) {
 	states := []string{"seed", "draft", "active", "iterating", "implemented", "archived", "rejected", "dropped"}
 	for _, s := range states {
@@ -44,7 +55,7 @@ func TestStateSymbol_Unknown(t *testing.T) {
 }
 
 func TestFormatListRow_NotEmpty(t *testing.T) {
-	row := tui.FormatListRow("My Idea", "aspiration", "active", "walk", "photography", false, 80)
+	row := tui.FormatListRow(denote.DefaultKindsConfig(), "My Idea", "aspiration", "active", "walk", "photography", false, 80)
 	if row == "" {
 		t.Error("expected non-empty row")
 	}
@@ -55,8 +66,8 @@ func TestFormatListRow_Selected(t *testing.T) {
 	lipgloss.SetColorProfile(termenv.ANSI256)
 	t.Cleanup(func() { lipgloss.SetColorProfile(termenv.Ascii) })
 
-	normal := tui.FormatListRow("Test", "note", "active", "", "", false, 80)
-	selected := tui.FormatListRow("Test", "note", "active", "", "", true, 80)
+	normal := tui.FormatListRow(denote.DefaultKindsConfig(), "Test", "note", "active", "", "", false, 80)
+	selected := tui.FormatListRow(denote.DefaultKindsConfig(), "Test", "note", "active", "", "", true, 80)
 	// Selected and normal should differ (different styling applied)
 	if normal == selected {
 		t.Error("expected selected and normal rows to render differently")
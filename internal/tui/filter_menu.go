@@ -0,0 +1,270 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	acoreui "github.com/mph-llm-experiments/acore/tui"
+	"github.com/mph-llm-experiments/anote/internal/denote"
+)
+
+// filterFields are the rows of the filter menu, in order.
+var filterFields = []string{FieldKind, FieldState, FieldPurpose, FieldTags, FieldMaturity, FieldPlanned, FieldQuery}
+
+// filterLabels names each filter in the menu and the header summary.
+var filterLabels = map[string]string{
+	FieldKind:     "kind",
+	FieldState:    "state",
+	FieldPurpose:  "purpose",
+	FieldTags:     "tag",
+	FieldMaturity: "maturity",
+	FieldPlanned:  "planned",
+	FieldQuery:    "query",
+}
+
+// plannedOptions are the planned-for filter choices.
+var plannedOptions = []string{"today", "overdue", "upcoming", "any", "none"}
+
+// effectiveKind returns kind, or aspiration when it is unset.
+func effectiveKind(kind string) string {
+	if kind == "" {
+		return denote.KindAspiration
+	}
+	return kind
+}
+
+// filterValue returns how the active filter on field reads, or "".
+func (m Model) filterValue(field string) string {
+	switch field {
+	case FieldKind:
+		return m.kindFilter
+	case FieldState:
+		if m.stateFilter == "" {
+			return ""
+		}
+		kind := m.stateKind
+		if kind == "" {
+			kind = m.kindFilter
+		}
+		return m.kindsConfig.DisplayState(m.stateFilter, kind)
+	case FieldPurpose:
+		if m.purposeFilter == "" {
+			return ""
+		}
+		if name := m.purposeNameFor(m.purposeFilter); name != "" {
+			return name
+		}
+		return m.purposeFilter
+	case FieldTags:
+		return strings.Join(m.tagFilters, ",")
+	case FieldMaturity:
+		return m.maturityFilter
+	case FieldPlanned:
+		return m.plannedFilter
+	case FieldQuery:
+		return m.searchQuery
+	}
+	return ""
+}
+
+// clearFilter removes the filter on field.
+func (m *Model) clearFilter(field string) {
+	switch field {
+	case FieldKind:
+		m.kindFilter = ""
+	case FieldState:
+		m.stateFilter, m.stateKind = "", ""
+	case FieldPurpose:
+		m.purposeFilter = ""
+	case FieldTags:
+		m.tagFilters = nil
+	case FieldMaturity:
+		m.maturityFilter = ""
+	case FieldPlanned:
+		m.plannedFilter = ""
+	case FieldQuery:
+		m.searchQuery = ""
+	}
+	m.activeView = ""
+}
+
+// clearFilters removes every filter.
+func (m *Model) clearFilters() {
+	for _, field := range filterFields {
+		m.clearFilter(field)
+	}
+}
+
+// hasFilters reports whether any filter is active.
+func (m Model) hasFilters() bool {
+	for _, field := range filterFields {
+		if m.filterValue(field) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// filterOptions returns the choices for field's picker. State choices are
+// display labels: those of the kind filter's kind, or of every kind.
+func (m Model) filterOptions(field string) []string {
+	switch field {
+	case FieldKind:
+		return m.kindsConfig.AllKinds()
+	case FieldState:
+		kinds := m.kindsConfig.AllKinds()
+		if m.kindFilter != "" {
+			kinds = []string{m.kindFilter}
+		}
+		var labels []string
+		seen := map[string]bool{}
+		for _, kind := range kinds {
+			for _, s := range m.kindsConfig.ValidStatesFor(kind) {
+				label := m.kindsConfig.DisplayState(s, kind)
+				if !seen[label] {
+					seen[label] = true
+					labels = append(labels, label)
+				}
+			}
+		}
+		return labels
+	case FieldPurpose:
+		ids := make([]string, 0, len(m.purposes))
+		for _, p := range m.purposes {
+			ids = append(ids, p.ID)
+		}
+		return ids
+	case FieldTags:
+		seen := map[string]bool{}
+		var tags []string
+		for _, idea := range m.ideas {
+			for _, t := range idea.Tags {
+				if t != "idea" && !seen[t] {
+					seen[t] = true
+					tags = append(tags, t)
+				}
+			}
+		}
+		sort.Strings(tags)
+		return tags
+	case FieldMaturity:
+		return []string{denote.MaturityCrawl, denote.MaturityWalk, denote.MaturityRun, "none"}
+	case FieldPlanned:
+		return plannedOptions
+	}
+	return nil
+}
+
+// setFilter applies a choice from field's picker.
+func (m *Model) setFilter(field, value string) {
+	switch field {
+	case FieldKind:
+		m.kindFilter = value
+	case FieldState:
+		m.stateFilter, m.stateKind = m.kindsConfig.ResolveDisplayState(value)
+	case FieldPurpose:
+		m.purposeFilter = value
+	case FieldMaturity:
+		m.maturityFilter = value
+	case FieldPlanned:
+		m.plannedFilter = value
+	}
+	m.activeView = ""
+}
+
+// matchesTags reports whether idea carries every filtered tag.
+func (m Model) matchesTags(idea *denote.Idea) bool {
+	for _, t := range m.tagFilters {
+		if !idea.HasTag(t) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Model) matchesMaturity(idea *denote.Idea) bool {
+	switch m.maturityFilter {
+	case "":
+		return true
+	case "none":
+		return idea.Maturity == ""
+	}
+	return idea.Maturity == m.maturityFilter
+}
+
+// matchesPlanned applies the planned-for filter; today is YYYY-MM-DD.
+func (m Model) matchesPlanned(idea *denote.Idea, today string) bool {
+	p := idea.PlannedFor
+	switch m.plannedFilter {
+	case "today":
+		return p == today
+	case "overdue":
+		return p != "" && p < today
+	case "upcoming":
+		return p > today
+	case "any":
+		return p != ""
+	case "none":
+		return p == ""
+	}
+	return true
+}
+
+// viewFilterMenu renders the filter menu overlay: the list of filters, or
+// the picker for one of them.
+func (m Model) viewFilterMenu() string {
+	var sb strings.Builder
+
+	if m.filterField == "" {
+		sb.WriteString(acoreui.HeaderStyle.Render("Filters:"))
+		sb.WriteString("\n")
+		for i, field := range m.menuOptions {
+			value := m.filterValue(field)
+			if value == "" {
+				value = "-"
+			}
+			line := fmt.Sprintf("%-9s %s", filterLabels[field], value)
+			if i == m.menuCursor {
+				sb.WriteString(acoreui.SelectedStyle.Render("  → " + line))
+			} else {
+				sb.WriteString(acoreui.MutedStyle.Render("    " + line))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString(acoreui.MutedStyle.Render("j/k: move  enter: choose  x: clear  X: clear all  esc: close"))
+		return sb.String()
+	}
+
+	sb.WriteString(acoreui.HeaderStyle.Render("Filter by " + filterLabels[m.filterField] + ":"))
+	sb.WriteString("\n")
+	if len(m.menuOptions) == 0 {
+		sb.WriteString(acoreui.MutedStyle.Render("    nothing to choose from"))
+		sb.WriteString("\n")
+	}
+	for i, opt := range m.menuOptions {
+		display := opt
+		switch m.filterField {
+		case FieldPurpose:
+			display = strings.Repeat("  ", m.purposeDepth(opt)) + m.purposeNameFor(opt)
+		case FieldTags:
+			mark := "[ ] "
+			if m.tagPicks[opt] {
+				mark = "[x] "
+			}
+			display = mark + opt
+		}
+		if i == m.menuCursor {
+			sb.WriteString(acoreui.SelectedStyle.Render("  → " + display))
+		} else {
+			sb.WriteString(acoreui.MutedStyle.Render("    " + display))
+		}
+		sb.WriteString("\n")
+	}
+	if m.filterField == FieldTags {
+		sb.WriteString(acoreui.MutedStyle.Render("j/k: move  space: toggle  enter: apply  esc: back"))
+	} else {
+		sb.WriteString(acoreui.MutedStyle.Render("j/k: move  enter: select  esc: back"))
+	}
+	return sb.String()
+}
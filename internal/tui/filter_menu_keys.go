@@ -0,0 +1,116 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openFilterMenu shows the list of filters (ModeFilterMenu).
+func (m Model) openFilterMenu(cursor int) Model {
+	m.filterField = ""
+	m.menuOptions = filterFields
+	m.menuCursor = cursor
+	m.mode = ModeFilterMenu
+	return m
+}
+
+// handleFilterMenuKey handles keys in the filter menu: the list of filters,
+// or a picker for one of them.
+func (m Model) handleFilterMenuKey(key string) (tea.Model, tea.Cmd) {
+	if m.filterField != "" {
+		return m.handleFilterPickerKey(key)
+	}
+
+	switch key {
+	case "j", "down":
+		if m.menuCursor < len(m.menuOptions)-1 {
+			m.menuCursor++
+		}
+	case "k", "up":
+		if m.menuCursor > 0 {
+			m.menuCursor--
+		}
+	case "x", "backspace", "delete":
+		m.clearFilter(m.menuOptions[m.menuCursor])
+		m.applyFilters()
+	case "X":
+		m.clearFilters()
+		m.applyFilters()
+	case "enter":
+		field := m.menuOptions[m.menuCursor]
+		if field == FieldQuery {
+			m.mode = ModeSearch
+			m.editBuf.SetValue(m.searchQuery)
+			return m, nil
+		}
+		options := m.filterOptions(field)
+		m.filterField = field
+		m.menuOptions = options
+		m.menuCursor = 0
+		current := m.filterValue(field)
+		if field == FieldPurpose {
+			current = m.purposeFilter
+		}
+		for i, opt := range options {
+			if opt == current {
+				m.menuCursor = i
+			}
+		}
+		if field == FieldTags {
+			m.tagPicks = map[string]bool{}
+			for _, t := range m.tagFilters {
+				m.tagPicks[t] = true
+			}
+		}
+	case "esc", "q", "F":
+		m.mode = ModeNormal
+	}
+	return m, nil
+}
+
+// handleFilterPickerKey handles keys in the picker for m.filterField. Enter
+// applies the choice and returns to the list of filters.
+func (m Model) handleFilterPickerKey(key string) (tea.Model, tea.Cmd) {
+	back := func(m Model) Model {
+		field := m.filterField
+		cursor := 0
+		for i, f := range filterFields {
+			if f == field {
+				cursor = i
+			}
+		}
+		return m.openFilterMenu(cursor)
+	}
+
+	switch key {
+	case "j", "down":
+		if m.menuCursor < len(m.menuOptions)-1 {
+			m.menuCursor++
+		}
+	case "k", "up":
+		if m.menuCursor > 0 {
+			m.menuCursor--
+		}
+	case " ":
+		if m.filterField == FieldTags && m.menuCursor < len(m.menuOptions) {
+			tag := m.menuOptions[m.menuCursor]
+			m.tagPicks[tag] = !m.tagPicks[tag]
+		}
+	case "enter":
+		if m.filterField == FieldTags {
+			m.tagFilters = nil
+			for _, tag := range m.menuOptions {
+				if m.tagPicks[tag] {
+					m.tagFilters = append(m.tagFilters, tag)
+				}
+			}
+			m.activeView = ""
+		} else if m.menuCursor < len(m.menuOptions) {
+			m.setFilter(m.filterField, m.menuOptions[m.menuCursor])
+		}
+		m.applyFilters()
+		m = back(m)
+	case "esc":
+		m = back(m)
+	}
+	return m, nil
+}
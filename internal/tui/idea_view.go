@@ -26,9 +26,9 @@ func (m Model) viewIdeaDetail() string {
 	sb.WriteString("\n\n")
 
 	// Metadata fields
-	sb.WriteString(m.renderMetaField("kind", KindSymbol(idea.Kind)+" "+idea.Kind, FieldKind))
+	sb.WriteString(m.renderMetaField("kind", KindSymbol(m.kindsConfig, idea.Kind)+" "+idea.Kind, FieldKind))
 	sb.WriteString(m.renderMetaField("state",
-		StateSymbol(idea.State)+" "+denote.DisplayState(idea.State, idea.Kind), FieldState))
+		StateSymbol(idea.State)+" "+m.kindsConfig.DisplayState(idea.State, idea.Kind), FieldState))
 	if idea.Maturity != "" {
 		sb.WriteString(m.renderMetaField("maturity", idea.Maturity, FieldMaturity))
 	} else {
@@ -82,7 +82,13 @@ func (m Model) viewIdeaDetail() string {
 		sb.WriteString("\n")
 		sb.WriteString(acoreui.ErrorStyle.Render(fmt.Sprintf("Delete %q? This cannot be undone.", idea.Title)))
 		sb.WriteString("\n")
-		sb.WriteString(acoreui.MutedStyle.Render("y: delete  n/esc: cancel"))
+		if n := m.attachedCount(idea.ID); idea.Kind == denote.KindPurpose && n > 0 {
+			sb.WriteString(acoreui.BodyStyle.Render(fmt.Sprintf("%d idea(s) are attached to this purpose.", n)))
+			sb.WriteString("\n")
+			sb.WriteString(acoreui.MutedStyle.Render("d: detach them and delete  r: reassign them and delete  n/esc: cancel"))
+		} else {
+			sb.WriteString(acoreui.MutedStyle.Render("y: delete  n/esc: cancel"))
+		}
 	}
 
 	// Footer
@@ -112,8 +118,12 @@ func (m Model) viewStateMenu() string {
 	}
 	var sb strings.Builder
 
-	if m.menuField == FieldPurpose {
-		sb.WriteString(acoreui.HeaderStyle.Render("Choose purpose:"))
+	if m.menuField == FieldPurpose || m.menuField == FieldReassign {
+		if m.menuField == FieldReassign {
+			sb.WriteString(acoreui.HeaderStyle.Render(fmt.Sprintf("Reassign ideas from %q to:", m.viewingIdea.Title)))
+		} else {
+			sb.WriteString(acoreui.HeaderStyle.Render("Choose purpose:"))
+		}
 		sb.WriteString("\n")
 		for i, opt := range m.menuOptions {
 			var display string
@@ -124,6 +134,7 @@ func (m Model) viewStateMenu() string {
 				if display == "" {
 					display = opt // fallback to raw ID if not found
 				}
+				display = strings.Repeat("  ", m.purposeDepth(opt)) + display
 			}
 			if i == m.menuCursor {
 				sb.WriteString(acoreui.SelectedStyle.Render("  → " + display))
@@ -133,10 +144,19 @@ func (m Model) viewStateMenu() string {
 			sb.WriteString("\n")
 		}
 	} else {
-		sb.WriteString(acoreui.HeaderStyle.Render("Choose state:"))
+		if m.menuForce {
+			sb.WriteString(acoreui.WarningStyle.Render("Choose state (all states, ignoring transitions):"))
+		} else {
+			sb.WriteString(acoreui.HeaderStyle.Render("Choose state:"))
+		}
 		sb.WriteString("\n")
+		if len(m.menuOptions) == 0 {
+			sb.WriteString(acoreui.MutedStyle.Render("    no transitions from " +
+				m.kindsConfig.DisplayState(m.viewingIdea.State, m.viewingIdea.Kind)))
+			sb.WriteString("\n")
+		}
 		for i, s := range m.menuOptions {
-			display := denote.DisplayState(s, m.viewingIdea.Kind)
+			display := m.kindsConfig.DisplayState(s, m.viewingIdea.Kind)
 			if i == m.menuCursor {
 				sb.WriteString(acoreui.SelectedStyle.Render("  → " + display))
 			} else {
@@ -145,7 +165,11 @@ func (m Model) viewStateMenu() string {
 			sb.WriteString("\n")
 		}
 	}
-	sb.WriteString(acoreui.MutedStyle.Render("j/k: move  enter: select  esc: cancel"))
+	if m.menuField == FieldState {
+		sb.WriteString(acoreui.MutedStyle.Render("j/k: move  enter: select  f: all states  esc: cancel"))
+	} else {
+		sb.WriteString(acoreui.MutedStyle.Render("j/k: move  enter: select  esc: cancel"))
+	}
 	return sb.String()
 }
 
@@ -161,7 +185,7 @@ func (m Model) viewCompliancePrompt() string {
 	)))
 	sb.WriteString("\n")
 	for i, s := range m.complianceOptions {
-		display := denote.DisplayState(s, m.viewingIdea.Kind)
+		display := m.kindsConfig.DisplayState(s, m.viewingIdea.Kind)
 		if i == m.complianceCursor {
 			sb.WriteString(acoreui.SelectedStyle.Render("  → " + display))
 		} else {
@@ -201,6 +225,28 @@ func (m Model) viewCreate() string {
 		sb.WriteString("\n")
 		sb.WriteString(acoreui.MutedStyle.Render("1-9: pick kind  enter: accept  esc: cancel"))
 		return sb.String()
+	case 3:
+		var sb strings.Builder
+		sb.WriteString(acoreui.HeaderStyle.Render("New note") + "\n\n")
+		sb.WriteString(fieldLabel.Render("Title: ") + acoreui.BodyStyle.Render(m.createTitle) + "\n")
+		sb.WriteString(fieldLabel.Render("Kind:  ") + acoreui.BodyStyle.Render(m.createKind) + "\n")
+		sb.WriteString(fieldLabel.Render("Tags:  ") + acoreui.BodyStyle.Render(m.createTags) + "\n\n")
+		sb.WriteString(fieldLabel.Render("Purpose:") + "\n")
+		for i, opt := range m.createPurposeOptions() {
+			display := "(unattached)"
+			if opt != "" {
+				display = m.purposeNameFor(opt)
+			}
+			if i == m.createPurposeCursor {
+				sb.WriteString(acoreui.SelectedStyle.Render("  → " + display))
+			} else {
+				sb.WriteString(acoreui.MutedStyle.Render("    " + display))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+		sb.WriteString(acoreui.MutedStyle.Render(fmt.Sprintf("%s ideas require a purpose  j/k: move  enter: save  esc: cancel", m.createKind)))
+		return sb.String()
 	default:
 		return acoreui.HeaderStyle.Render("New note") + "\n\n" +
 			fieldLabel.Render("Title: ") + acoreui.BodyStyle.Render(m.createTitle) + "\n" +
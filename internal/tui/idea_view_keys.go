@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"fmt"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/mph-llm-experiments/anote/internal/denote"
 )
@@ -19,12 +21,10 @@ func (m Model) handleIdeaViewKey(key string) (tea.Model, tea.Cmd) {
 		}
 
 	case "s":
-		// Open kind-aware state picker
+		// Open kind-aware state picker, limited to states reachable from the current one
 		if m.viewingIdea != nil {
-			m.menuOptions = m.kindsConfig.ValidStatesFor(m.viewingIdea.Kind)
-			if m.menuOptions == nil {
-				m.menuOptions = []string{}
-			}
+			m.menuForce = false
+			m.menuOptions = m.stateMenuOptions()
 			m.menuCursor = 0
 			m.menuField = FieldState
 			m.mode = ModeStateMenu
@@ -36,8 +36,15 @@ func (m Model) handleIdeaViewKey(key string) (tea.Model, tea.Cmd) {
 			// Build options list: empty string (unattached) + all purpose IDs
 			m.menuOptions = make([]string, 0, len(m.purposes)+1)
 			m.menuOptions = append(m.menuOptions, "") // unattached
+			// A purpose's parent cannot be the purpose itself or one beneath it
+			var exclude map[string]bool
+			if m.viewingIdea.Kind == denote.KindPurpose {
+				exclude = denote.PurposeSubtree(m.purposeParents, m.viewingIdea.ID)
+			}
 			for _, p := range m.purposes {
-				m.menuOptions = append(m.menuOptions, p.ID)
+				if !exclude[p.ID] {
+					m.menuOptions = append(m.menuOptions, p.ID)
+				}
 			}
 			m.menuCursor = 0
 			m.menuField = FieldPurpose
@@ -57,7 +64,7 @@ func (m Model) handleIdeaViewKey(key string) (tea.Model, tea.Cmd) {
 			default:
 				m.viewingIdea.Maturity = ""
 			}
-			if err := persistIdeaFrontmatter(m.viewingIdea); err != nil {
+			if err := m.persistIdeaFrontmatter(m.viewingIdea); err != nil {
 				m.statusMsg = "error saving maturity: " + err.Error()
 			} else {
 				if fresh, err := refreshIdea(m.viewingIdea); err == nil {
@@ -81,7 +88,7 @@ func (m Model) handleIdeaViewKey(key string) (tea.Model, tea.Cmd) {
 					break
 				}
 			}
-			if err := persistIdeaFrontmatter(m.viewingIdea); err != nil {
+			if err := m.persistIdeaFrontmatter(m.viewingIdea); err != nil {
 				m.statusMsg = "error saving kind: " + err.Error()
 			} else {
 				if fresh, err := refreshIdea(m.viewingIdea); err == nil {
@@ -113,9 +120,16 @@ func (m Model) handleIdeaViewKey(key string) (tea.Model, tea.Cmd) {
 			newTitle := m.editBuf.Value()
 			if newTitle != "" {
 				m.viewingIdea.Title = newTitle
-				if err := persistIdeaFrontmatter(m.viewingIdea); err != nil {
+				if err := m.persistIdeaFrontmatter(m.viewingIdea); err != nil {
 					m.statusMsg = "error saving title: " + err.Error()
 				} else {
+					if m.viewingIdea.Kind == denote.KindPurpose {
+						if n, err := syncPurposeName(m.cfg, m.viewingIdea); err != nil {
+							m.statusMsg = "error updating attached ideas: " + err.Error()
+						} else if n > 0 {
+							m.statusMsg = fmt.Sprintf("Updated purpose name on %d idea(s)", n)
+						}
+					}
 					if fresh, err := refreshIdea(m.viewingIdea); err == nil {
 						m.viewingIdea = fresh
 					}
@@ -136,7 +150,7 @@ func (m Model) handleIdeaViewKey(key string) (tea.Model, tea.Cmd) {
 
 	case "E":
 		if m.viewingIdea != nil && m.viewingIdea.FilePath != "" {
-			return m, openInEditor(m.viewingIdea.FilePath)
+			return m, openInEditor(m.cfg, m.viewingIdea.FilePath)
 		}
 
 	default:
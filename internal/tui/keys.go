@@ -1,11 +1,14 @@
 package tui
 
 import (
-	"os"
+	"fmt"
 	"os/exec"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mph-llm-experiments/anote/internal/config"
+	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/idea"
 )
 
 // Update implements tea.Model.
@@ -47,6 +50,12 @@ func (m Model) handleKey(key string) (tea.Model, tea.Cmd) {
 		return m.handleTagsEditKey(key)
 	case ModeConfirmDelete:
 		return m.handleConfirmDeleteKey(key)
+	case ModeViewMenu:
+		return m.handleViewMenuKey(key)
+	case ModeSortMenu:
+		return m.handleSortMenuKey(key)
+	case ModeFilterMenu:
+		return m.handleFilterMenuKey(key)
 	case ModeHelp:
 		m.mode = ModeNormal
 		return m, nil
@@ -156,13 +165,42 @@ func (m Model) handleNormalKey(key string) (tea.Model, tea.Cmd) {
 		m.applyFilters()
 		return m, nil
 
+	case "S":
+		m.menuOptions = idea.SortKeys
+		m.menuCursor = 0
+		for i, k := range m.menuOptions {
+			if k == m.sortBy {
+				m.menuCursor = i
+			}
+		}
+		m.mode = ModeSortMenu
+		return m, nil
+
+	case "V":
+		// Saved views from the config file; the first entry clears them
+		names := m.cfg.ViewNames()
+		if len(names) == 0 {
+			m.statusMsg = "no saved views: add [views.NAME] tables to the config file"
+			return m, nil
+		}
+		m.menuOptions = append([]string{""}, names...)
+		m.menuCursor = 0
+		for i, name := range m.menuOptions {
+			if name == m.activeView {
+				m.menuCursor = i
+			}
+		}
+		m.statusMsg = ""
+		m.mode = ModeViewMenu
+		return m, nil
+
+	case "F":
+		return m.openFilterMenu(0), nil
+
 	case "esc":
 		// Clear active filters on esc
-		if m.kindFilter != "" || m.stateFilter != "" || m.purposeFilter != "" || m.searchQuery != "" {
-			m.kindFilter = ""
-			m.stateFilter = ""
-			m.purposeFilter = ""
-			m.searchQuery = ""
+		if m.hasFilters() {
+			m.clearFilters()
 			m.applyFilters()
 		}
 		return m, nil
@@ -191,7 +229,23 @@ func (m Model) handleSearchKey(key string) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// handleMenuKey handles j/k/enter/esc in the state picker menu (ModeStateMenu).
+// stateMenuOptions returns the states offered by the state picker: those
+// reachable from the viewing idea's current state, or every valid state for
+// its kind when the menu is forced.
+func (m Model) stateMenuOptions() []string {
+	var options []string
+	if m.menuForce {
+		options = m.kindsConfig.ValidStatesFor(m.viewingIdea.Kind)
+	} else {
+		options = m.kindsConfig.NextStates(m.viewingIdea.Kind, m.viewingIdea.State)
+	}
+	if options == nil {
+		options = []string{}
+	}
+	return options
+}
+
+// handleMenuKey handles j/k/f/enter/esc in the state picker menu (ModeStateMenu).
 func (m Model) handleMenuKey(key string) (tea.Model, tea.Cmd) {
 	switch key {
 	case "j", "down":
@@ -202,13 +256,32 @@ func (m Model) handleMenuKey(key string) (tea.Model, tea.Cmd) {
 		if m.menuCursor > 0 {
 			m.menuCursor--
 		}
+	case "f":
+		// Human override: toggle between reachable and all valid states
+		if m.menuField == FieldState && m.viewingIdea != nil {
+			m.menuForce = !m.menuForce
+			m.menuOptions = m.stateMenuOptions()
+			m.menuCursor = 0
+		}
 	case "enter":
 		if m.viewingIdea != nil && m.menuCursor < len(m.menuOptions) {
 			selected := m.menuOptions[m.menuCursor]
 			switch m.menuField {
+			case FieldReassign:
+				return m.finishDeletePurpose(selected)
 			case FieldState:
+				if !m.menuForce {
+					if err := m.kindsConfig.ValidateTransition(m.viewingIdea.Kind, m.viewingIdea.State, selected); err != nil {
+						m.statusMsg = "error saving state: " + err.Error()
+						break
+					}
+				}
+				if m.viewingIdea.PurposeID == "" && m.kindsConfig.PurposeRequiredFor(m.viewingIdea.Kind, selected) {
+					m.statusMsg = fmt.Sprintf("error saving state: %s ideas require a purpose (p to attach)", m.viewingIdea.Kind)
+					break
+				}
 				m.viewingIdea.State = selected
-				if err := persistIdeaFrontmatter(m.viewingIdea); err != nil {
+				if err := m.persistIdeaFrontmatter(m.viewingIdea); err != nil {
 					m.statusMsg = "error saving state: " + err.Error()
 				} else {
 					if fresh, err := refreshIdea(m.viewingIdea); err == nil {
@@ -218,13 +291,18 @@ func (m Model) handleMenuKey(key string) (tea.Model, tea.Cmd) {
 				}
 			case FieldPurpose:
 				if selected == "" {
+					if m.kindsConfig.PurposeRequiredFor(m.viewingIdea.Kind, m.viewingIdea.State) {
+						m.statusMsg = fmt.Sprintf("error saving purpose: %s ideas require a purpose when %s",
+							m.viewingIdea.Kind, m.kindsConfig.DisplayState(m.viewingIdea.State, m.viewingIdea.Kind))
+						break
+					}
 					m.viewingIdea.PurposeID = ""
 					m.viewingIdea.PurposeName = ""
 				} else {
 					m.viewingIdea.PurposeID = selected
 					m.viewingIdea.PurposeName = m.purposeNameFor(selected)
 				}
-				if err := persistIdeaFrontmatter(m.viewingIdea); err != nil {
+				if err := m.persistIdeaFrontmatter(m.viewingIdea); err != nil {
 					m.statusMsg = "error saving purpose: " + err.Error()
 				} else {
 					if fresh, err := refreshIdea(m.viewingIdea); err == nil {
@@ -241,6 +319,64 @@ func (m Model) handleMenuKey(key string) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleViewMenuKey handles j/k/enter/esc in the saved view switcher (ModeViewMenu).
+func (m Model) handleViewMenuKey(key string) (tea.Model, tea.Cmd) {
+	switch key {
+	case "j", "down":
+		if m.menuCursor < len(m.menuOptions)-1 {
+			m.menuCursor++
+		}
+	case "k", "up":
+		if m.menuCursor > 0 {
+			m.menuCursor--
+		}
+	case "enter":
+		if m.menuCursor < len(m.menuOptions) {
+			m.loadView(m.menuOptions[m.menuCursor])
+		}
+		m.mode = ModeNormal
+	case "esc", "q":
+		m.mode = ModeNormal
+	}
+	return m, nil
+}
+
+// handleSortMenuKey handles j/k/r/enter/esc in the sort menu (ModeSortMenu).
+// The choice is saved for the next session.
+func (m Model) handleSortMenuKey(key string) (tea.Model, tea.Cmd) {
+	switch key {
+	case "j", "down":
+		if m.menuCursor < len(m.menuOptions)-1 {
+			m.menuCursor++
+		}
+	case "k", "up":
+		if m.menuCursor > 0 {
+			m.menuCursor--
+		}
+	case "r":
+		m.reverseSort = !m.reverseSort
+		m.saveSort()
+		m.applyFilters()
+	case "enter":
+		if m.menuCursor < len(m.menuOptions) {
+			m.sortBy = m.menuOptions[m.menuCursor]
+			m.saveSort()
+			m.applyFilters()
+		}
+		m.mode = ModeNormal
+	case "esc", "q":
+		m.mode = ModeNormal
+	}
+	return m, nil
+}
+
+// saveSort remembers the current sort for the next session.
+func (m *Model) saveSort() {
+	if err := config.SaveUIState(config.UIState{SortBy: m.sortBy, ReverseSort: m.reverseSort}); err != nil {
+		m.statusMsg = "error saving sort: " + err.Error()
+	}
+}
+
 func (m Model) handleComplianceKey(key string) (tea.Model, tea.Cmd) {
 	switch key {
 	case "j", "down":
@@ -254,7 +390,7 @@ func (m Model) handleComplianceKey(key string) (tea.Model, tea.Cmd) {
 	case "enter":
 		if m.viewingIdea != nil && m.complianceCursor < len(m.complianceOptions) {
 			m.viewingIdea.State = m.complianceOptions[m.complianceCursor]
-			if err := persistIdeaFrontmatter(m.viewingIdea); err != nil {
+			if err := m.persistIdeaFrontmatter(m.viewingIdea); err != nil {
 				m.statusMsg = "error saving state: " + err.Error()
 			} else {
 				if fresh, err := refreshIdea(m.viewingIdea); err == nil {
@@ -271,7 +407,80 @@ func (m Model) handleComplianceKey(key string) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// createPurposeOptions returns the purpose IDs offered by the create form's
+// purpose step. "" (no purpose) is offered unless kinds.json makes a missing
+// purpose fatal.
+func (m Model) createPurposeOptions() []string {
+	options := make([]string, 0, len(m.purposes)+1)
+	if !m.kindsConfig.FailOnMissingPurpose() {
+		options = append(options, "")
+	}
+	for _, p := range m.purposes {
+		options = append(options, p.ID)
+	}
+	return options
+}
+
+// finishCreate creates the idea from the create form and opens it.
+func (m Model) finishCreate(purposeID string) (tea.Model, tea.Cmd) {
+	// Parse space-separated tags
+	var tags []string
+	for _, t := range strings.Fields(m.createTags) {
+		if t != "" {
+			tags = append(tags, t)
+		}
+	}
+	var purpose *denote.Idea
+	for idx := range m.purposes {
+		if m.purposes[idx].ID == purposeID {
+			purpose = &m.purposes[idx]
+		}
+	}
+	created, err := m.createIdea(m.createTitle, m.createKind, tags, purpose)
+	if err != nil {
+		m.statusMsg = "error creating idea: " + err.Error()
+		m.mode = ModeNormal
+		m.editBuf.Clear()
+		return m, nil
+	}
+	if purpose == nil && m.kindsConfig.PurposeRequired(created.Kind) {
+		m.statusMsg = fmt.Sprintf("warning: %s ideas require a purpose (p to attach)", created.Kind)
+	}
+	_ = m.loadIdeas()
+	m.viewingIdea = created
+	m.mode = ModeIdeaView
+	m.editBuf.Clear()
+	// Open in editor so user can write the body immediately.
+	return m, openInEditor(m.cfg, created.FilePath)
+}
+
+// handleCreatePurposeKey handles the purpose step of the create form.
+func (m Model) handleCreatePurposeKey(key string) (tea.Model, tea.Cmd) {
+	options := m.createPurposeOptions()
+	switch key {
+	case "j", "down":
+		if m.createPurposeCursor < len(options)-1 {
+			m.createPurposeCursor++
+		}
+	case "k", "up":
+		if m.createPurposeCursor > 0 {
+			m.createPurposeCursor--
+		}
+	case "enter":
+		if m.createPurposeCursor < len(options) {
+			return m.finishCreate(options[m.createPurposeCursor])
+		}
+	case "esc":
+		m.mode = ModeNormal
+		m.editBuf.Clear()
+	}
+	return m, nil
+}
+
 func (m Model) handleCreateKey(key string) (tea.Model, tea.Cmd) {
+	if m.createField == 3 {
+		return m.handleCreatePurposeKey(key)
+	}
 	switch key {
 	case "enter":
 		if m.createField == 0 {
@@ -287,26 +496,13 @@ func (m Model) handleCreateKey(key string) (tea.Model, tea.Cmd) {
 			m.editBuf.SetValue("")
 		} else {
 			m.createTags = m.editBuf.Value()
-			// Parse space-separated tags
-			var tags []string
-			for _, t := range strings.Fields(m.createTags) {
-				if t != "" {
-					tags = append(tags, t)
-				}
-			}
-			created, err := createIdea(m.cfg, m.createTitle, m.createKind, tags)
-			if err != nil {
-				m.statusMsg = "error creating idea: " + err.Error()
-				m.mode = ModeNormal
-				m.editBuf.Clear()
+			// Purpose-required kinds get a purpose step before saving
+			if m.kindsConfig.PurposeRequired(m.createKind) && len(m.purposes) > 0 {
+				m.createField = 3
+				m.createPurposeCursor = 0
 				return m, nil
 			}
-			_ = m.loadIdeas()
-			m.viewingIdea = created
-			m.mode = ModeIdeaView
-			m.editBuf.Clear()
-			// Open in editor so user can write the body immediately.
-			return m, openInEditor(created.FilePath)
+			return m.finishCreate("")
 		}
 	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
 		// On kind field, number keys select directly
@@ -339,7 +535,7 @@ func (m Model) handleLogEntryKey(key string) (tea.Model, tea.Cmd) {
 	case "enter":
 		entry := m.logBuf.Value()
 		if entry != "" && m.viewingIdea != nil {
-			if err := persistLogEntry(m.viewingIdea, entry); err != nil {
+			if err := m.persistLogEntry(m.viewingIdea, entry); err != nil {
 				m.statusMsg = "error saving log: " + err.Error()
 			} else {
 				if fresh, err := refreshIdea(m.viewingIdea); err == nil {
@@ -375,7 +571,7 @@ func (m Model) handleTagsEditKey(key string) (tea.Model, tea.Cmd) {
 				}
 			}
 			m.viewingIdea.Tags = newTags
-			if err := persistIdeaFrontmatter(m.viewingIdea); err != nil {
+			if err := m.persistIdeaFrontmatter(m.viewingIdea); err != nil {
 				m.statusMsg = "error saving tags: " + err.Error()
 			} else {
 				if fresh, err := refreshIdea(m.viewingIdea); err == nil {
@@ -397,11 +593,65 @@ func (m Model) handleTagsEditKey(key string) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// finishDeletePurpose deletes the viewing purpose after moving its ideas to
+// purposeID, or detaching them when purposeID is "".
+func (m Model) finishDeletePurpose(purposeID string) (tea.Model, tea.Cmd) {
+	var to *denote.Idea
+	for idx := range m.purposes {
+		if m.purposes[idx].ID == purposeID {
+			to = &m.purposes[idx]
+		}
+	}
+	n, err := m.deletePurpose(m.viewingIdea, to)
+	if err != nil {
+		m.statusMsg = "error deleting: " + err.Error()
+		m.mode = ModeIdeaView
+		_ = m.loadIdeas()
+		return m, nil
+	}
+	if to != nil {
+		m.statusMsg = fmt.Sprintf("Deleted: %s (%d idea(s) moved to %s)", m.viewingIdea.Title, n, to.Title)
+	} else {
+		m.statusMsg = fmt.Sprintf("Deleted: %s (%d idea(s) detached)", m.viewingIdea.Title, n)
+	}
+	m.viewingIdea = nil
+	m.mode = ModeNormal
+	_ = m.loadIdeas()
+	return m, nil
+}
+
 func (m Model) handleConfirmDeleteKey(key string) (tea.Model, tea.Cmd) {
+	// A purpose with attached ideas must say what happens to them first
+	if m.viewingIdea != nil && m.viewingIdea.Kind == denote.KindPurpose && m.attachedCount(m.viewingIdea.ID) > 0 {
+		switch key {
+		case "d":
+			return m.finishDeletePurpose("")
+		case "r":
+			// Not the purpose or one beneath it: its sub-purposes move too
+			subtree := denote.PurposeSubtree(m.purposeParents, m.viewingIdea.ID)
+			m.menuOptions = make([]string, 0, len(m.purposes))
+			for _, p := range m.purposes {
+				if !subtree[p.ID] {
+					m.menuOptions = append(m.menuOptions, p.ID)
+				}
+			}
+			if len(m.menuOptions) == 0 {
+				m.statusMsg = "no other purpose to reassign to"
+				return m, nil
+			}
+			m.menuCursor = 0
+			m.menuField = FieldReassign
+			m.mode = ModeStateMenu
+		case "n", "esc", "q":
+			m.mode = ModeIdeaView
+		}
+		return m, nil
+	}
+
 	switch key {
 	case "y":
 		if m.viewingIdea != nil {
-			if err := deleteIdea(m.viewingIdea); err != nil {
+			if err := m.deleteIdea(m.viewingIdea); err != nil {
 				m.statusMsg = "error deleting: " + err.Error()
 				m.mode = ModeIdeaView
 			} else {
@@ -420,14 +670,12 @@ func (m Model) handleConfirmDeleteKey(key string) (tea.Model, tea.Cmd) {
 // editorReturnMsg is sent after $EDITOR exits.
 type editorReturnMsg struct{}
 
-// openInEditor opens the file at path in $EDITOR, suspending the TUI.
-// Sends editorReturnMsg when the editor exits so the model can refresh.
-func openInEditor(filePath string) tea.Cmd {
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vi"
-	}
-	c := exec.Command(editor, filePath)
+// openInEditor opens the file at path in the editor from
+// cfg.EditorCommand, suspending the TUI. Sends editorReturnMsg when the
+// editor exits so the model can refresh.
+func openInEditor(cfg *config.Config, filePath string) tea.Cmd {
+	editor := cfg.EditorCommand()
+	c := exec.Command(editor[0], append(editor[1:], filePath)...)
 	return tea.ExecProcess(c, func(_ error) tea.Msg {
 		return editorReturnMsg{}
 	})
@@ -1,19 +1,26 @@
 package tui
 
 import (
+	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	acoreui "github.com/mph-llm-experiments/acore/tui"
 	"github.com/mph-llm-experiments/anote/internal/config"
 	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/hooks"
+	"github.com/mph-llm-experiments/anote/internal/idea"
 )
 
 // Model is the root Bubble Tea model for the anote TUI.
 type Model struct {
 	cfg         *config.Config
 	kindsConfig *denote.KindsConfig
+	hooks       *hooks.Runner
+	mutator     *idea.Mutator
 
 	// List state
 	ideas    []denote.Idea
@@ -28,10 +35,20 @@ type Model struct {
 	mode Mode
 
 	// Filters
-	kindFilter    string
-	stateFilter   string
-	purposeFilter string
-	searchQuery   string
+	kindFilter     string
+	stateFilter    string
+	stateKind      string // kind implied by a kind-specific state label such as considering
+	purposeFilter  string
+	tagFilters     []string
+	maturityFilter string // a maturity, or "none"
+	plannedFilter  string // today, overdue, upcoming, any or none
+	searchQuery    string
+	searchErr      string // why searchQuery does not parse, shown in the footer
+	activeView     string // saved view the filters were last loaded from
+
+	// Filter menu: "" lists the filters, otherwise the field being picked
+	filterField string
+	tagPicks    map[string]bool // tags toggled in the tag picker
 
 	// Sort
 	sortBy      string
@@ -46,15 +63,18 @@ type Model struct {
 	menuOptions []string
 	menuCursor  int
 	menuField   string // which field the menu is editing
+	menuForce   bool   // state menu lists all states, bypassing the transition graph
 
 	// Create mode
-	createTitle string
-	createKind  string
-	createTags  string
-	createField int
+	createTitle         string
+	createKind          string
+	createTags          string
+	createField         int
+	createPurposeCursor int // purpose picker step, shown for purpose-required kinds
 
-	// Purposes (cached list of purpose-kind ideas)
-	purposes []denote.Idea
+	// Purposes (cached list of purpose-kind ideas, in hierarchy order)
+	purposes       []denote.Idea
+	purposeParents map[string]string // purpose ID -> parent purpose ID
 
 	// Compliance prompt
 	complianceOptions []string
@@ -74,15 +94,32 @@ func NewModel(cfg *config.Config) (*Model, error) {
 		return nil, err
 	}
 
+	hk, err := hooks.New(cfg.IdeasDirectory, cfg.Hooks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hooks config: %w", err)
+	}
+	mutator, err := idea.NewMutator(cfg.IdeasDirectory, kindsConfig, cfg.Rules, hk)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules config: %w", err)
+	}
+
 	m := &Model{
 		cfg:         cfg,
 		kindsConfig: kindsConfig,
+		hooks:       hk,
+		mutator:     mutator,
 		nav:         acoreui.NewNavigationHandler(0, false),
 		editBuf:     acoreui.NewEditBuffer(""),
 		logBuf:      acoreui.NewEditBuffer(""),
 		sortBy:      "modified",
 	}
 
+	// Sort chosen in an earlier session
+	if st := config.LoadUIState(); idea.IsSortKey(st.SortBy) {
+		m.sortBy = st.SortBy
+		m.reverseSort = st.ReverseSort
+	}
+
 	if err := m.loadIdeas(); err != nil {
 		return nil, err
 	}
@@ -103,13 +140,27 @@ func (m *Model) loadIdeas() error {
 		m.ideas = append(m.ideas, *p)
 	}
 
-	// Cache purposes (ideas where Kind == KindPurpose)
-	m.purposes = make([]denote.Idea, 0)
+	// Cache purposes (ideas where Kind == KindPurpose), parents before children
+	var entries []denote.IndexEntry
+	byID := make(map[string]denote.Idea)
 	for _, idea := range m.ideas {
 		if idea.Kind == denote.KindPurpose {
-			m.purposes = append(m.purposes, idea)
+			entries = append(entries, denote.IndexEntry{
+				ID: idea.ID, IndexID: idea.IndexID, Title: idea.Title, Kind: idea.Kind, PurposeID: idea.PurposeID,
+			})
+			byID[idea.ID] = idea
+		}
+	}
+	m.purposeParents = denote.PurposeParents(entries)
+	m.purposes = make([]denote.Idea, 0, len(entries))
+	var walk func(nodes []*denote.PurposeNode)
+	walk = func(nodes []*denote.PurposeNode) {
+		for _, n := range nodes {
+			m.purposes = append(m.purposes, byID[n.Entry.ID])
+			walk(n.Children)
 		}
 	}
+	walk(denote.BuildPurposeTree(entries))
 
 	m.applyFilters()
 	return nil
@@ -117,6 +168,30 @@ func (m *Model) loadIdeas() error {
 
 // applyFilters filters m.ideas into m.filtered based on active filters.
 func (m *Model) applyFilters() {
+	// The purpose filter takes in ideas attached to any sub-purpose
+	var purposeSet map[string]bool
+	if m.purposeFilter != "" {
+		purposeSet = denote.PurposeSubtree(m.purposeParents, m.purposeFilter)
+	}
+
+	// The / query uses the same language as list -q. While it doesn't parse
+	// (often because it is still being typed) it filters nothing.
+	var query *idea.Filter
+	m.searchErr = ""
+	if strings.TrimSpace(m.searchQuery) != "" {
+		ptrs := make([]*denote.Idea, len(m.purposes))
+		for i := range m.purposes {
+			ptrs[i] = &m.purposes[i]
+		}
+		f, err := idea.CompileFilter(m.searchQuery, m.kindsConfig, idea.PurposeEntries(ptrs), time.Now())
+		if err != nil {
+			m.searchErr = err.Error()
+		} else {
+			query = f
+		}
+	}
+
+	today := time.Now().Format("2006-01-02")
 	filtered := make([]denote.Idea, 0, len(m.ideas))
 	for _, idea := range m.ideas {
 		if m.kindFilter != "" && idea.Kind != m.kindFilter {
@@ -125,25 +200,96 @@ func (m *Model) applyFilters() {
 		if m.stateFilter != "" && idea.State != m.stateFilter {
 			continue
 		}
-		if m.purposeFilter != "" && idea.PurposeID != m.purposeFilter {
+		if m.stateKind != "" && effectiveKind(idea.Kind) != m.stateKind {
+			continue
+		}
+		if purposeSet != nil && !purposeSet[idea.PurposeID] {
+			continue
+		}
+		if !m.matchesTags(&idea) || !m.matchesMaturity(&idea) || !m.matchesPlanned(&idea, today) {
 			continue
 		}
-		if m.searchQuery != "" && !containsFold(idea.Title, m.searchQuery) {
+		if query != nil && !query.Match(&idea) {
 			continue
 		}
 		filtered = append(filtered, idea)
 	}
-	// Sort by modified descending (most recently touched first — "riffle through the deck")
-	sort.Slice(filtered, func(i, j int) bool {
-		return filtered[i].ModTime.After(filtered[j].ModTime)
+	// Default: modified descending (most recently touched first — "riffle through the deck")
+	less := idea.Less(m.sortBy)
+	sort.SliceStable(filtered, func(i, j int) bool {
+		if m.reverseSort {
+			return less(&filtered[j], &filtered[i])
+		}
+		return less(&filtered[i], &filtered[j])
 	})
 
 	m.filtered = filtered
 	m.nav.SetMax(len(m.filtered))
 }
 
-func containsFold(s, substr string) bool {
-	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+// loadView replaces the filters with those of the named saved view, or
+// clears them when name is "". The view's query goes into the / query, as
+// does purpose none, which the purpose filter can't express. A view with a
+// sort also sets the sort.
+func (m *Model) loadView(name string) {
+	m.clearFilters()
+	m.activeView = name
+	if name == "" {
+		m.applyFilters()
+		return
+	}
+
+	v, err := m.cfg.LookupView(name)
+	if err != nil {
+		m.statusMsg = err.Error()
+		m.activeView = ""
+		m.applyFilters()
+		return
+	}
+	m.kindFilter = v.Kind
+	if v.State != "" {
+		m.stateFilter, m.stateKind = m.kindsConfig.ResolveDisplayState(v.State)
+	}
+	if v.Tag != "" {
+		m.tagFilters = []string{v.Tag}
+	}
+
+	var terms []string
+	if v.Purpose != "" {
+		if strings.EqualFold(v.Purpose, "none") {
+			terms = append(terms, "purpose:none")
+		} else if id := m.findPurpose(v.Purpose); id != "" {
+			m.purposeFilter = id
+		} else {
+			m.statusMsg = fmt.Sprintf("view %s: purpose %q not found", name, v.Purpose)
+		}
+	}
+	if v.Query != "" {
+		if len(terms) > 0 {
+			terms = append(terms, "("+v.Query+")")
+		} else {
+			terms = append(terms, v.Query)
+		}
+	}
+	m.searchQuery = strings.Join(terms, " ")
+
+	if v.Sort != "" {
+		m.sortBy = v.Sort
+		m.reverseSort = v.Reverse
+	}
+	m.applyFilters()
+}
+
+// findPurpose resolves a purpose by index_id, ID or title among the cached
+// purposes, returning its ID or "".
+func (m *Model) findPurpose(ref string) string {
+	n, numErr := strconv.Atoi(ref)
+	for _, p := range m.purposes {
+		if (numErr == nil && p.IndexID == n) || p.ID == ref || strings.EqualFold(p.Title, ref) {
+			return p.ID
+		}
+	}
+	return ""
 }
 
 // purposeNameFor looks up the purpose name by ID from the cached purposes list.
@@ -159,6 +305,28 @@ func (m *Model) purposeNameFor(purposeID string) string {
 	return ""
 }
 
+// purposeDepth returns how many ancestors the purpose has.
+func (m *Model) purposeDepth(purposeID string) int {
+	depth := 0
+	seen := map[string]bool{purposeID: true}
+	for p := m.purposeParents[purposeID]; p != "" && !seen[p]; p = m.purposeParents[p] {
+		seen[p] = true
+		depth++
+	}
+	return depth
+}
+
+// attachedCount returns how many loaded ideas are attached to purposeID.
+func (m *Model) attachedCount(purposeID string) int {
+	n := 0
+	for _, idea := range m.ideas {
+		if idea.PurposeID == purposeID {
+			n++
+		}
+	}
+	return n
+}
+
 // Init implements tea.Model.
 func (m Model) Init() tea.Cmd { return nil }
 
@@ -8,20 +8,30 @@ import (
 
 	"github.com/mph-llm-experiments/anote/internal/config"
 	"github.com/mph-llm-experiments/anote/internal/denote"
+	"github.com/mph-llm-experiments/anote/internal/hooks"
 	"github.com/mph-llm-experiments/anote/internal/idea"
 )
 
 // persistIdeaFrontmatter writes updated frontmatter for the idea, updating the
-// Modified timestamp. The idea's FilePath must be set.
-func persistIdeaFrontmatter(idea *denote.Idea) error {
-	idea.Modified = time.Now().Format(time.RFC3339)
-	return denote.UpdateIdeaFrontmatter(idea.FilePath, idea)
+// Modified timestamp. The idea's FilePath must be set. Saving applies the
+// configured rules and state-change hooks; if it fails the idea is put
+// back as it is on disk.
+func (m *Model) persistIdeaFrontmatter(i *denote.Idea) error {
+	change := &idea.Change{Idea: i}
+	if err := m.mutator.Save(change); err != nil {
+		if fresh, rerr := refreshIdea(i); rerr == nil {
+			*i = *fresh
+		}
+		return err
+	}
+	m.warnHook(change.Warning)
+	return nil
 }
 
 // persistLogEntry appends a timestamped log entry to the idea file.
 // It re-reads the file first to extract the current body content, then writes
 // the full file back with the log entry appended.
-func persistLogEntry(i *denote.Idea, entry string) error {
+func (m *Model) persistLogEntry(i *denote.Idea, entry string) error {
 	// Re-read the file to get current content (avoids overwriting out-of-band edits)
 	fresh, err := denote.ParseIdeaFile(i.FilePath)
 	if err != nil {
@@ -29,27 +39,68 @@ func persistLogEntry(i *denote.Idea, entry string) error {
 	}
 	content := extractContent(fresh.Content)
 	newContent := appendLogEntry(content, entry)
-	i.Modified = time.Now().Format(time.RFC3339)
-	return denote.WriteIdeaFile(i.FilePath, i, newContent)
+	idea.Touch(i)
+	if err := denote.WriteIdeaFile(i.FilePath, i, newContent); err != nil {
+		return err
+	}
+	m.warnHook(m.hooks.Run(hooks.PostLog, hooks.Payload{Idea: i, OldState: i.State, NewState: i.State, Message: entry}))
+	return nil
 }
 
 // createIdea creates a new idea file and returns the parsed result.
-func createIdea(cfg *config.Config, title, kind string, tags []string) (*denote.Idea, error) {
-	return idea.CreateIdea(cfg.IdeasDirectory, title, tags, kind, "")
+// purpose may be nil.
+func (m *Model) createIdea(title, kind string, tags []string, purpose *denote.Idea) (*denote.Idea, error) {
+	created, err := idea.Create(m.cfg.IdeasDirectory, idea.NewIdea{Title: title, Tags: tags, Kind: kind, Purpose: purpose, Hooks: m.hooks})
+	if err != nil {
+		return nil, err
+	}
+	m.warnHook(m.hooks.Run(hooks.PostCreate, hooks.Payload{Idea: created, NewState: created.State}))
+	return created, nil
 }
 
-// deleteIdea removes the idea file from disk.
-func deleteIdea(i *denote.Idea) error {
+// deleteIdea removes the idea file from disk unless a pre-delete hook
+// objects.
+func (m *Model) deleteIdea(i *denote.Idea) error {
+	if err := m.hooks.Run(hooks.PreDelete, hooks.Payload{Idea: i, OldState: i.State}); err != nil {
+		return err
+	}
 	return os.Remove(i.FilePath)
 }
 
+// deletePurpose moves the ideas attached to purpose onto to (or detaches
+// them when to is nil), then deletes the purpose. It returns the number of
+// ideas moved. The pre-delete hook runs before any idea is moved.
+func (m *Model) deletePurpose(purpose, to *denote.Idea) (int, error) {
+	if err := m.hooks.Run(hooks.PreDelete, hooks.Payload{Idea: purpose, OldState: purpose.State}); err != nil {
+		return 0, err
+	}
+	n, err := idea.ReassignPurpose(m.cfg.IdeasDirectory, purpose.ID, to)
+	if err != nil {
+		return n, err
+	}
+	return n, os.Remove(purpose.FilePath)
+}
+
+// warnHook shows a failed post- hook in the status line. The change it
+// followed stands.
+func (m *Model) warnHook(err error) {
+	if err != nil {
+		m.statusMsg = "warning: " + err.Error()
+	}
+}
+
+// syncPurposeName copies a renamed purpose's title to its attached ideas.
+func syncPurposeName(cfg *config.Config, purpose *denote.Idea) (int, error) {
+	return idea.SyncPurposeName(cfg.IdeasDirectory, purpose)
+}
+
 // refreshIdea re-reads the idea from disk, returning the fresh version.
 func refreshIdea(i *denote.Idea) (*denote.Idea, error) {
 	return denote.ParseIdeaFile(i.FilePath)
 }
 
 // extractContent extracts the body content after YAML frontmatter.
-// Mirrors the CLI's extractIdeaContent function.
+// Mirrors idea.ExtractContent.
 func extractContent(fullContent string) string {
 	if !strings.HasPrefix(fullContent, "---\n") {
 		return fullContent
@@ -68,7 +119,7 @@ func extractContent(fullContent string) string {
 }
 
 // appendLogEntry appends a timestamped entry to the ## Log section.
-// Mirrors the CLI's addLogEntry function.
+// Mirrors idea.AddLogEntry.
 func appendLogEntry(content, message string) string {
 	now := time.Now().Format("2006-01-02")
 	entry := "- **" + now + "** " + message
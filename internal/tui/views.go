@@ -43,6 +43,7 @@ func (m Model) viewList() string {
 		idea := m.filtered[i]
 		selected := i == cursor
 		row := FormatListRow(
+			m.kindsConfig,
 			idea.Title,
 			idea.Kind,
 			idea.State,
@@ -60,10 +61,85 @@ func (m Model) viewList() string {
 		sb.WriteString("\n")
 	}
 
+	switch m.mode {
+	case ModeViewMenu:
+		sb.WriteString("\n")
+		sb.WriteString(m.viewViewMenu())
+		sb.WriteString("\n")
+	case ModeSortMenu:
+		sb.WriteString("\n")
+		sb.WriteString(m.viewSortMenu())
+		sb.WriteString("\n")
+	case ModeFilterMenu:
+		sb.WriteString("\n")
+		sb.WriteString(m.viewFilterMenu())
+		sb.WriteString("\n")
+	}
+
 	sb.WriteString(m.renderFooter())
 	return sb.String()
 }
 
+// viewViewMenu renders the saved view switcher overlay.
+func (m Model) viewViewMenu() string {
+	var sb strings.Builder
+	sb.WriteString(acoreui.HeaderStyle.Render("Switch view:"))
+	sb.WriteString("\n")
+	for i, name := range m.menuOptions {
+		display := "(all ideas, no filters)"
+		if name != "" {
+			display = name
+			if d := m.cfg.Views[name].Description; d != "" {
+				display += "  " + d
+			}
+		}
+		if i == m.menuCursor {
+			sb.WriteString(acoreui.SelectedStyle.Render("  → " + display))
+		} else {
+			sb.WriteString(acoreui.MutedStyle.Render("    " + display))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(acoreui.MutedStyle.Render("j/k: move  enter: select  esc: cancel"))
+	return sb.String()
+}
+
+// sortLabels describes each sort key's natural direction.
+var sortLabels = map[string]string{
+	"modified":    "modified (newest first)",
+	"created":     "created (newest first)",
+	"title":       "title (A-Z)",
+	"index":       "index",
+	"state":       "state (seed first)",
+	"maturity":    "maturity (crawl first)",
+	"planned_for": "planned for (soonest first)",
+}
+
+// viewSortMenu renders the sort menu overlay.
+func (m Model) viewSortMenu() string {
+	var sb strings.Builder
+	title := "Sort by:"
+	if m.reverseSort {
+		title = "Sort by (reversed):"
+	}
+	sb.WriteString(acoreui.HeaderStyle.Render(title))
+	sb.WriteString("\n")
+	for i, key := range m.menuOptions {
+		display := sortLabels[key]
+		if display == "" {
+			display = key
+		}
+		if i == m.menuCursor {
+			sb.WriteString(acoreui.SelectedStyle.Render("  → " + display))
+		} else {
+			sb.WriteString(acoreui.MutedStyle.Render("    " + display))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(acoreui.MutedStyle.Render("j/k: move  enter: select  r: reverse  esc: close"))
+	return sb.String()
+}
+
 func (m Model) renderHeader() string {
 	count := fmt.Sprintf("%d notes", len(m.filtered))
 	if len(m.filtered) != len(m.ideas) {
@@ -85,21 +161,25 @@ func (m Model) renderHeader() string {
 
 func (m Model) activeFilterSummary() string {
 	parts := []string{}
-	if m.kindFilter != "" {
-		parts = append(parts, "kind:"+m.kindFilter)
-	}
-	if m.stateFilter != "" {
-		parts = append(parts, "state:"+m.stateFilter)
+	if m.activeView != "" {
+		parts = append(parts, "view:"+m.activeView)
 	}
-	if m.purposeFilter != "" {
-		purposeName := m.purposeNameFor(m.purposeFilter)
-		if purposeName == "" {
-			purposeName = m.purposeFilter
+	for _, field := range filterFields {
+		value := m.filterValue(field)
+		switch {
+		case value == "":
+		case field == FieldQuery:
+			parts = append(parts, "/"+value)
+		default:
+			parts = append(parts, filterLabels[field]+":"+value)
 		}
-		parts = append(parts, "purpose:"+purposeName)
 	}
-	if m.searchQuery != "" {
-		parts = append(parts, "/"+m.searchQuery)
+	if m.sortBy != "modified" || m.reverseSort {
+		sortDesc := "sort:" + m.sortBy
+		if m.reverseSort {
+			sortDesc += " reversed"
+		}
+		parts = append(parts, sortDesc)
 	}
 	if len(parts) == 0 {
 		return ""
@@ -108,7 +188,10 @@ func (m Model) activeFilterSummary() string {
 }
 
 func (m Model) renderFooter() string {
-	hints := "c:new  /:search  enter:open  K:kind  P:purpose  ?:help  q:quit"
+	hints := "c:new  /:search  enter:open  F:filter  K:kind  P:purpose  ?:help  q:quit"
+	if m.searchErr != "" {
+		hints = "query: " + m.searchErr
+	}
 	if m.statusMsg != "" {
 		hints = m.statusMsg
 	}
@@ -122,11 +205,14 @@ func (m Model) viewHelp() string {
 		{Key: "ctrl+d/u", Desc: "page down/up"},
 		{Key: "enter", Desc: "open idea"},
 		{Key: "c", Desc: "create new idea"},
-		{Key: "/", Desc: "search by title"},
+		{Key: "/", Desc: "filter by query (as list -q)"},
 		{Key: "K", Desc: "filter by kind (list) / change kind (idea view)"},
 		{Key: "P", Desc: "filter by purpose"},
+		{Key: "F", Desc: "filter menu: state, tags, maturity, planned (x clears one)"},
+		{Key: "V", Desc: "switch saved view"},
+		{Key: "S", Desc: "sort (remembered between sessions)"},
 		{Key: "?", Desc: "this help"},
-		{Key: "q / esc", Desc: "back / quit"},
+		{Key: "q / esc", Desc: "back / quit (esc clears filters)"},
 		{Key: "— idea view —", Desc: ""},
 		{Key: "s", Desc: "change state (kind-aware)"},
 		{Key: "p", Desc: "change purpose"},
@@ -136,7 +222,7 @@ func (m Model) viewHelp() string {
 		{Key: "l", Desc: "add log entry"},
 		{Key: "r", Desc: "rename title"},
 		{Key: "x", Desc: "delete note"},
-		{Key: "E", Desc: "open in $EDITOR"},
+		{Key: "E", Desc: "open in editor"},
 	}
 	return acoreui.RenderHelp(bindings, m.width)
 }